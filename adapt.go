@@ -0,0 +1,135 @@
+package wrap
+
+import (
+	"database/sql"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+)
+
+// An Adapter converts a well-known error type from another library into an error with structured
+// log attributes, for use with [Adapt]. If the adapter doesn't recognize the error, it returns
+// false.
+//
+// Adapters should keep the given error in the chain of the returned error (e.g. by using
+// [AddAttrs]), so that [errors.Is] and [errors.As] still work on the adapted error.
+type Adapter func(err error) (adapted error, ok bool)
+
+// Adapt converts the given error with the first of the given adapters that recognizes it, and
+// returns the error unchanged if none do. If no adapters are given, the built-in adapters
+// ([AdaptNetError], [AdaptSQLError] and [AdaptOSError]) are used.
+//
+// Call Adapt at the boundary where errors from other libraries enter your code, so that the details
+// hidden in their error types end up as log attributes:
+//
+//	file, err := os.Open(path)
+//	if err != nil {
+//		return wrap.Error(wrap.Adapt(err), "failed to open config")
+//	}
+//
+// The error string is left unchanged. If the given error is nil, Adapt returns nil.
+func Adapt(err error, adapters ...Adapter) error {
+	if err == nil {
+		return nil
+	}
+	if len(adapters) == 0 {
+		adapters = defaultAdapters
+	}
+
+	for _, adapter := range adapters {
+		if adapted, ok := adapter(err); ok {
+			return adapted
+		}
+	}
+	return err
+}
+
+var defaultAdapters = []Adapter{AdaptNetError, AdaptSQLError, AdaptOSError}
+
+// AdaptNetError is an [Adapter] for errors from the [net] package. It attaches the following log
+// attributes, where available:
+//   - For [*net.OpError]: "net_op" (e.g. "dial"), "network" (e.g. "tcp") and "addr" (the remote
+//     address)
+//   - For [*net.DNSError]: "dns_name" and "dns_not_found"
+//   - For all [net.Error] values: "timeout"
+func AdaptNetError(err error) (adapted error, ok bool) {
+	var attrs []any
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		attrs = append(attrs, "net_op", opErr.Op, "network", opErr.Net)
+		if opErr.Addr != nil {
+			attrs = append(attrs, "addr", opErr.Addr.String())
+		}
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		attrs = append(attrs, "dns_name", dnsErr.Name, "dns_not_found", dnsErr.IsNotFound)
+	}
+	if netErr, ok := findNetError(err); ok {
+		attrs = append(attrs, "timeout", netErr.Timeout())
+	}
+
+	if len(attrs) == 0 {
+		return err, false
+	}
+	return AddAttrs(err, attrs...), true
+}
+
+// Returns the first net.Error in the given error's chain, skipping markers (which implement
+// net.Error by forwarding to the errors they wrap, see withNetError).
+func findNetError(err error) (netErr net.Error, ok bool) {
+	forEachInChain(err, func(err error) {
+		if _, isMarker := err.(markerError); isMarker || ok {
+			return
+		}
+		netErr, ok = err.(net.Error)
+	})
+	return netErr, ok
+}
+
+// AdaptSQLError is an [Adapter] for the sentinel errors of the [database/sql] package. It attaches
+// the log attribute "sql_error", with the value "no_rows" for [sql.ErrNoRows], "tx_done" for
+// [sql.ErrTxDone] and "conn_done" for [sql.ErrConnDone]. Errors from database drivers are not
+// recognized, since their types are driver-specific.
+func AdaptSQLError(err error) (adapted error, ok bool) {
+	var kind string
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		kind = "no_rows"
+	case errors.Is(err, sql.ErrTxDone):
+		kind = "tx_done"
+	case errors.Is(err, sql.ErrConnDone):
+		kind = "conn_done"
+	default:
+		return err, false
+	}
+	return AddAttrs(err, "sql_error", kind), true
+}
+
+// AdaptOSError is an [Adapter] for file system and system call errors from the [os] and [io/fs]
+// packages. It attaches the following log attributes:
+//   - For [*fs.PathError]: "fs_op" (e.g. "open") and "path"
+//   - For [*os.LinkError]: "fs_op" (e.g. "rename"), "old_path" and "new_path"
+//   - For [*os.SyscallError]: "syscall"
+func AdaptOSError(err error) (adapted error, ok bool) {
+	var attrs []any
+
+	var pathErr *fs.PathError
+	var linkErr *os.LinkError
+	if errors.As(err, &pathErr) {
+		attrs = append(attrs, "fs_op", pathErr.Op, "path", pathErr.Path)
+	} else if errors.As(err, &linkErr) {
+		attrs = append(attrs, "fs_op", linkErr.Op, "old_path", linkErr.Old, "new_path", linkErr.New)
+	}
+	var syscallErr *os.SyscallError
+	if errors.As(err, &syscallErr) {
+		attrs = append(attrs, "syscall", syscallErr.Syscall)
+	}
+
+	if len(attrs) == 0 {
+		return err, false
+	}
+	return AddAttrs(err, attrs...), true
+}
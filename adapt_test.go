@@ -0,0 +1,80 @@
+package wrap_test
+
+import (
+	"database/sql"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAdaptOSError(t *testing.T) {
+	err := &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist}
+	adapted := wrap.Adapt(err)
+
+	assertEqualErrorStrings(t, adapted, err.Error())
+	assertEqualAttrs(t, adapted, []slog.Attr{
+		slog.String("fs_op", "open"),
+		slog.String("path", "/etc/app.conf"),
+	})
+	if !errors.Is(adapted, fs.ErrNotExist) {
+		t.Error("expected errors.Is to return true for adapted error")
+	}
+
+	linkErr := &os.LinkError{Op: "rename", Old: "a", New: "b", Err: fs.ErrExist}
+	assertEqualAttrs(t, wrap.Adapt(linkErr), []slog.Attr{
+		slog.String("fs_op", "rename"),
+		slog.String("old_path", "a"),
+		slog.String("new_path", "b"),
+	})
+}
+
+func TestAdaptNetError(t *testing.T) {
+	err := &net.OpError{
+		Op:   "dial",
+		Net:  "tcp",
+		Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5432},
+		Err:  errors.New("connection refused"),
+	}
+	wrapped := wrap.Error(wrap.Adapt(err), "failed to connect to database")
+
+	assertEqualAttrSlices(t, wrap.Attrs(wrapped), []slog.Attr{
+		slog.String("net_op", "dial"),
+		slog.String("network", "tcp"),
+		slog.String("addr", "127.0.0.1:5432"),
+		slog.Bool("timeout", false),
+	})
+
+	var opErr *net.OpError
+	if !errors.As(wrapped, &opErr) {
+		t.Error("expected errors.As to find *net.OpError")
+	}
+}
+
+func TestAdaptSQLError(t *testing.T) {
+	err := wrap.Error(sql.ErrNoRows, "user not found")
+	assertEqualAttrs(t, wrap.Adapt(err), []slog.Attr{slog.String("sql_error", "no_rows")})
+}
+
+func TestAdaptWithCustomAdapters(t *testing.T) {
+	err := errors.New("error")
+
+	if adapted := wrap.Adapt(err); adapted != err {
+		t.Errorf("expected unrecognized error to be returned unchanged, got %v", adapted)
+	}
+	if wrap.Adapt(nil) != nil {
+		t.Error("expected Adapt to return nil for nil error")
+	}
+
+	unrecognized := func(err error) (error, bool) { return err, false }
+	custom := func(err error) (error, bool) { return wrap.AddAttrs(err, "custom", true), true }
+	assertEqualAttrs(
+		t,
+		wrap.Adapt(err, unrecognized, custom),
+		[]slog.Attr{slog.Bool("custom", true)},
+	)
+}
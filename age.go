@@ -0,0 +1,55 @@
+package wrap
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var recordCreationTimes atomic.Bool
+
+// SetRecordCreationTimes sets whether the wrapping functions in this package should record when
+// each layer was wrapped, for use with [Age]. Recording costs a clock read per wrap, so it is
+// disabled by default.
+func SetRecordCreationTimes(record bool) {
+	recordCreationTimes.Store(record)
+}
+
+// Age returns how long ago the given error was created, for catching bugs where stale errors are
+// returned (e.g. from long-lived caches or retried state machines). The creation time is the
+// earliest of the times recorded for the layers of the error (if enabled with
+// [SetRecordCreationTimes]), and the times that errors in the chain were cached (see [Cached]). It
+// returns false if no time is recorded for any layer.
+//
+// Example:
+//
+//	wrap.SetRecordCreationTimes(true)
+//	err := wrap.Error(errors.New("user not found"), "lookup failed")
+//	time.Sleep(time.Second)
+//	age, _ := wrap.Age(err)
+//	fmt.Println(age.Round(time.Second))
+//	// 1s
+//
+// Use [hermannm.dev/wrap/wrapreport.WarnStale] to be warned when old errors are reported.
+func Age(err error) (age time.Duration, ok bool) {
+	var createdAt time.Time
+	forEachInChain(err, func(err error) {
+		var layerTime time.Time
+		switch layer := err.(type) {
+		case interface{ callSite() *callSite }:
+			if site := layer.callSite(); site != nil {
+				layerTime = site.createdAt
+			}
+		case *cachedError:
+			layerTime = layer.cachedAt
+		}
+
+		if !layerTime.IsZero() && (createdAt.IsZero() || layerTime.Before(createdAt)) {
+			createdAt = layerTime
+		}
+	})
+
+	if createdAt.IsZero() {
+		return 0, false
+	}
+	return time.Since(createdAt), true
+}
@@ -0,0 +1,50 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAge(t *testing.T) {
+	wrap.SetRecordCreationTimes(true)
+	defer wrap.SetRecordCreationTimes(false)
+
+	before := time.Now()
+	err := wrap.Error(errors.New("user not found"), "lookup failed")
+	err = wrap.Error(err, "request failed")
+
+	age, ok := wrap.Age(err)
+	if !ok {
+		t.Fatal("expected error to have an age")
+	}
+	if maxAge := time.Since(before); age < 0 || age > maxAge {
+		t.Errorf("expected age between 0 and %v, got %v", maxAge, age)
+	}
+
+	if trail := wrap.Trail(err); len(trail) != 0 {
+		t.Errorf("expected no trail entries when only creation times are recorded, got %+v", trail)
+	}
+}
+
+func TestAgeOfCachedError(t *testing.T) {
+	wrap.SetRecordCreationTimes(true)
+	defer wrap.SetRecordCreationTimes(false)
+
+	cachedAt := time.Now().Add(-time.Hour)
+	err := wrap.Error(wrap.Cached(errors.New("user not found"), cachedAt), "lookup failed")
+
+	if age, _ := wrap.Age(err); age < time.Hour {
+		t.Errorf("expected age of the cached error, got %v", age)
+	}
+}
+
+func TestAgeDisabled(t *testing.T) {
+	err := wrap.Error(errors.New("error"), "wrapped error")
+
+	if _, ok := wrap.Age(err); ok {
+		t.Error("expected no age when creation times are not recorded")
+	}
+}
@@ -0,0 +1,92 @@
+package wrap
+
+import (
+	"slices"
+)
+
+// AppendMessage is the message used by [Append] when it creates a new multi-error.
+const AppendMessage = "multiple errors"
+
+// Append adds the given errors to err, for accumulating errors (e.g. from validation) without
+// nesting a new level for each one. Nil errors are ignored.
+//   - If err is a multi-error created by [Errors], Append returns a copy of it with the errors
+//     appended to its wrapped errors, keeping its message
+//   - If err is nil, and a single non-nil error is given, Append returns that error
+//   - Otherwise, Append creates a new multi-error with err and the given errors, with the message
+//     [AppendMessage]
+//
+// To give the accumulated errors a message of your own, start with an empty multi-error:
+//
+//	err := wrap.Errors("validation failed")
+//	if len(user.Name) > 64 {
+//		err = wrap.Append(err, errors.New("username too long"))
+//	}
+//	if !strings.Contains(user.Email, "@") {
+//		err = wrap.Append(err, errors.New("invalid email"))
+//	}
+//	fmt.Println(err)
+//	// validation failed
+//	// - username too long
+//	// - invalid email
+//
+// If err is nil and no non-nil errors are given, Append returns nil. The given multi-error is never
+// modified, so it is safe to append to the same error from different places.
+func Append(err error, more ...error) error {
+	more = slices.DeleteFunc(slices.Clone(more), isNil)
+	if len(more) == 0 {
+		return err
+	}
+
+	// Looks through the net.Error marker, so that multi-errors containing network errors are
+	// extended like other multi-errors, instead of being nested
+	existing := err
+	if marker, ok := err.(*netMarkerError); ok {
+		existing = marker.wrapped
+	}
+
+	switch existing := existing.(type) {
+	case nil:
+		if len(more) == 1 {
+			return more[0]
+		}
+	case *wrappedErrors:
+		// Keeps the caller of the original multi-error, since appending doesn't change where it
+		// was created
+		appended := *existing
+		appended.wrapped = append(slices.Clip(existing.wrapped), more...)
+		appended.isIndex = newIsIndex(appended.wrapped)
+		return runWrapHook(&appended)
+	default:
+		more = append([]error{err}, more...)
+	}
+
+	return runWrapHook(&wrappedErrors{
+		message: AppendMessage,
+		wrapped: more,
+		caller:  recordCaller(),
+		isIndex: newIsIndex(more),
+	})
+}
+
+// Join combines the given errors into one, like [errors.Join], but with the format of this
+// package: the errors are listed under the message [AppendMessage] (see [Errors]), instead of being
+// concatenated with newlines. Nil errors are ignored, and if all the given errors are nil, Join
+// returns nil.
+//
+// Example:
+//
+//	err := wrap.Join(errors.New("username too long"), nil, errors.New("invalid email"))
+//	fmt.Println(err)
+//	// multiple errors
+//	// - username too long
+//	// - invalid email
+//
+// Unlike errors.Join, Join returns a single non-nil error as is, since listing it under a generic
+// message would only add noise.
+func Join(errs ...error) error {
+	return Append(nil, errs...)
+}
+
+func isNil(err error) bool {
+	return err == nil
+}
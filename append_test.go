@@ -0,0 +1,73 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAppend(t *testing.T) {
+	err1 := errors.New("username too long")
+	err2 := errors.New("invalid email")
+	err3 := errors.New("invalid phone number")
+
+	validationErr := wrap.Errors("validation failed")
+	validationErr = wrap.Append(validationErr, err1, nil)
+	appended := wrap.Append(validationErr, err2, err3)
+	wrap.Append(validationErr, errors.New("other error"))
+
+	expected := `validation failed
+- username too long
+- invalid email
+- invalid phone number`
+
+	assertEqualErrorStrings(t, appended, expected)
+
+	if !errors.Is(appended, err1) || !errors.Is(appended, err3) {
+		t.Error("expected errors.Is to return true for appended errors")
+	}
+}
+
+func TestAppendToNonMultiError(t *testing.T) {
+	err1 := errors.New("username too long")
+	err2 := errors.New("invalid email")
+
+	var err error
+	err = wrap.Append(err, nil)
+	if err != nil {
+		t.Fatalf("expected nil when appending nil errors, got %v", err)
+	}
+	err = wrap.Append(err, err1)
+	if err != err1 {
+		t.Fatalf("expected single error to be returned as-is, got %v", err)
+	}
+	err = wrap.Append(err, err2)
+
+	expected := `multiple errors
+- username too long
+- invalid email`
+
+	assertEqualErrorStrings(t, err, expected)
+}
+
+func TestJoin(t *testing.T) {
+	err1 := errors.New("username too long")
+	err2 := errors.New("invalid email")
+
+	expected := `multiple errors
+- username too long
+- invalid email`
+
+	assertEqualErrorStrings(t, wrap.Join(err1, nil, err2), expected)
+
+	if !errors.Is(wrap.Join(err1, err2), err2) {
+		t.Error("expected errors.Is to return true for joined error")
+	}
+	if err := wrap.Join(nil, nil); err != nil {
+		t.Errorf("expected nil when joining nil errors, got %v", err)
+	}
+	if err := wrap.Join(nil, err1); err != err1 {
+		t.Errorf("expected single error to be returned as-is, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package wrap
+
+import (
+	"sync/atomic"
+)
+
+// AtomicError holds an error that can be augmented by concurrent goroutines, such as middleware
+// layers in a concurrent pipeline that each add attributes to the error flowing through it. Every
+// addition replaces the held error with a new head that wraps the previous one (see the package
+// documentation on immutability), and additions made concurrently are all kept.
+//
+// The zero value holds a nil error, ready to use. An AtomicError must not be copied after first
+// use.
+type AtomicError struct {
+	err atomic.Pointer[error]
+}
+
+// NewAtomicError creates an [AtomicError] holding the given error.
+func NewAtomicError(err error) *AtomicError {
+	var atomicErr AtomicError
+	atomicErr.Store(err)
+	return &atomicErr
+}
+
+// Load returns the error currently held.
+func (atomicErr *AtomicError) Load() error {
+	if err := atomicErr.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Store replaces the held error with the given error.
+func (atomicErr *AtomicError) Store(err error) {
+	atomicErr.err.Store(&err)
+}
+
+// AddAttrs attaches the given structured log attributes to the held error (on the same format as
+// [ErrorWithAttrs]), like [AddAttrs], and returns the new head. If another goroutine changes the
+// held error at the same time, the attributes are attached to its result instead, so that neither
+// addition is lost. If the held error is nil, AddAttrs does nothing and returns nil.
+func (atomicErr *AtomicError) AddAttrs(attrs ...any) error {
+	// Parses the attributes once, outside the compare-and-swap loop
+	parsed := newAttrs(attrs)
+
+	for {
+		current := atomicErr.err.Load()
+		if current == nil || *current == nil {
+			return nil
+		}
+
+		augmented := withNetError(&attrsMarkerError{wrapped: *current, attrs: parsed})
+		if atomicErr.err.CompareAndSwap(current, &augmented) {
+			// Runs the hook only for the error that was stored, not for ones lost to races
+			if hook := wrapHook.Load(); hook != nil {
+				(*hook)(augmented)
+			}
+			return augmented
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAtomicError(t *testing.T) {
+	root := errors.New("connection refused")
+	shared := wrap.Error(root, "request failed")
+	atomicErr := wrap.NewAtomicError(shared)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			atomicErr.AddAttrs("layer_"+strconv.Itoa(i), i)
+		}(i)
+	}
+	wg.Wait()
+
+	err := atomicErr.Load()
+	if attrs := wrap.Attrs(err); len(attrs) != 50 {
+		t.Errorf("expected all 50 concurrently added attributes, got %d", len(attrs))
+	}
+	if err.Error() != shared.Error() || !errors.Is(err, root) {
+		t.Errorf("expected augmented error to keep the shared chain, got %v", err)
+	}
+	if attrs := wrap.Attrs(shared); len(attrs) != 0 {
+		t.Errorf("expected shared error to be left unchanged, got %v", attrs)
+	}
+}
+
+func TestAtomicErrorZeroValue(t *testing.T) {
+	var atomicErr wrap.AtomicError
+	if err := atomicErr.AddAttrs("key", "value"); err != nil {
+		t.Errorf("expected nil when holding nil error, got %v", err)
+	}
+
+	atomicErr.Store(errors.New("error"))
+	head := atomicErr.AddAttrs("key", "value")
+	if head != atomicErr.Load() {
+		t.Error("expected AddAttrs to return the new held error")
+	}
+	assertEqualAttrSlices(t, wrap.Attrs(head), []slog.Attr{slog.String("key", "value")})
+}
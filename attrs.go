@@ -0,0 +1,359 @@
+package wrap
+
+import (
+	"log/slog"
+	"slices"
+	"sync/atomic"
+
+	"hermannm.dev/wrap/errschema"
+)
+
+// ErrorWithAttrs wraps the given error with a message for context, and attaches the given
+// structured log attributes to it. The attributes are not included in the error string, but are
+// available to logging libraries through the LogAttrs method (used by e.g.
+// [hermannm.dev/devlog/log]).
+//
+// Attributes are given on the same format as [slog.Logger.Info]: either [slog.Attr] values or
+// alternating string keys and values.
+//
+// Example:
+//
+//	err := errors.New("username already taken")
+//	wrapped := wrap.ErrorWithAttrs(err, "failed to create user", "username", "hermannm")
+//	fmt.Println(wrapped)
+//	// failed to create user
+//	// - username already taken
+//
+// The returned error implements the Unwrap method from the standard errors package, so it works
+// with [errors.Is] and [errors.As].
+func ErrorWithAttrs(wrapped error, message string, attrs ...any) error {
+	return runWrapHook(&errorWithAttrs{
+		wrappedError: wrappedError{wrapped: wrapped, message: message, caller: recordCaller()},
+		attrs:        newAttrs(attrs),
+	})
+}
+
+// NewErrorWithAttrs creates a new error with the given message, and attaches the given structured
+// log attributes to it (on the same format as [ErrorWithAttrs]). Use this instead of [errors.New]
+// when you want to attach attributes to a root error.
+func NewErrorWithAttrs(message string, attrs ...any) error {
+	return runWrapHook(&leafErrorWithAttrs{message: message, attrs: newAttrs(attrs)})
+}
+
+type leafErrorWithAttrs struct {
+	message string
+	attrs   []slog.Attr
+	private bool
+	mergedAttrsCache
+}
+
+func (err *leafErrorWithAttrs) Error() string {
+	return err.message
+}
+
+// LogAttrs returns the structured log attributes attached to this error, for logging libraries that
+// look for this method (such as [hermannm.dev/devlog/log]).
+func (err *leafErrorWithAttrs) LogAttrs() []slog.Attr {
+	return err.attrs
+}
+
+type errorWithAttrs struct {
+	wrappedError
+	attrs   []slog.Attr
+	private bool
+	mergedAttrsCache
+}
+
+// LogAttrs returns the structured log attributes attached to this error (not including attributes
+// of the errors it wraps), for logging libraries that look for this method (such as
+// [hermannm.dev/devlog/log]).
+func (err *errorWithAttrs) LogAttrs() []slog.Attr {
+	return err.attrs
+}
+
+// AddAttrs attaches the given structured log attributes to the error (on the same format as
+// [ErrorWithAttrs]), without adding a message. Use this when you want to record extra data (such as
+// an ID or a count) without having anything new to say in a message, since the error string is left
+// unchanged:
+//
+//	err := errors.New("user not found")
+//	withAttrs := wrap.AddAttrs(err, "user_id", 123)
+//	wrapped := wrap.Error(withAttrs, "failed to fetch user")
+//	fmt.Println(wrapped)
+//	// failed to fetch user
+//	// - user not found
+//
+// If the given error is nil, AddAttrs returns nil. The returned error implements the Unwrap method
+// from the standard errors package, so it works with [errors.Is] and [errors.As].
+func AddAttrs(err error, attrs ...any) error {
+	if err == nil {
+		return nil
+	}
+	return runWrapHook(&attrsMarkerError{wrapped: err, attrs: newAttrs(attrs)})
+}
+
+// AddPrivateAttrs attaches the given structured log attributes to the error without adding a
+// message, like [AddAttrs], but marks them as private to this layer: they are available on the
+// layer itself (through its LogAttrs method and [Walk]), but are not inherited by the errors that
+// wrap it, so they are left out when [Attrs] collects attributes from a wrapping error.
+//
+// Use this for noisy low-level diagnostics that are useful when inspecting the layer, but would
+// drown out the high-level attributes in aggregated log output. Attributes are inherited by
+// default. Use [Builder.PrivateAttrs] to mark the attributes of a layer with a message as private.
+func AddPrivateAttrs(err error, attrs ...any) error {
+	if err == nil {
+		return nil
+	}
+	return runWrapHook(&attrsMarkerError{wrapped: err, attrs: newAttrs(attrs), private: true})
+}
+
+// ParseAttrs converts the given log attributes to [slog.Attr] values. The attributes are given on
+// the same format as [slog.Logger.Info]: either [slog.Attr] values or alternating string keys and
+// values. Like slog, it uses the key "!BADKEY" for values without a valid key.
+func ParseAttrs(unparsed []any) []slog.Attr {
+	count, allAttrs := countAttrs(unparsed)
+	attrs := make([]slog.Attr, 0, count)
+
+	// Fast path for attributes given as slog.Attr values, which is common in attribute-heavy code
+	if allAttrs {
+		for _, attr := range unparsed {
+			attrs = append(attrs, attr.(slog.Attr))
+		}
+		return attrs
+	}
+
+	for i := 0; i < len(unparsed); i++ {
+		switch attr := unparsed[i].(type) {
+		case slog.Attr:
+			attrs = append(attrs, attr)
+		case string:
+			if i+1 >= len(unparsed) {
+				attrs = append(attrs, slog.String(badKey, attr))
+			} else {
+				attrs = append(attrs, slog.Any(attr, unparsed[i+1]))
+				i++
+			}
+		default:
+			attrs = append(attrs, slog.Any(badKey, attr))
+		}
+	}
+
+	return attrs
+}
+
+// Returns the number of attributes that ParseAttrs will produce from the given unparsed attributes
+// (string keys consume the following value), and whether they are all slog.Attr values.
+func countAttrs(unparsed []any) (count int, allAttrs bool) {
+	allAttrs = true
+	for i := 0; i < len(unparsed); i++ {
+		switch unparsed[i].(type) {
+		case slog.Attr:
+		case string:
+			allAttrs = false
+			i++
+		default:
+			allAttrs = false
+		}
+		count++
+	}
+	return count, allAttrs
+}
+
+// Same key as used by slog for attributes without a valid key.
+const badKey = "!BADKEY"
+
+// DefaultMaxAttrs is the default limit for the number of attributes per error (see
+// [SetMaxAttrs]).
+const DefaultMaxAttrs = 128
+
+// DroppedAttrsKey is the log attribute key for the number of attributes dropped from an error
+// because it exceeded the limit set by [SetMaxAttrs].
+const DroppedAttrsKey = "dropped_attrs"
+
+// The limit set by SetMaxAttrs, or nil to use DefaultMaxAttrs.
+var maxAttrs atomic.Pointer[int]
+
+// SetMaxAttrs sets the maximum number of structured log attributes that are stored on a single
+// error (by e.g. [ErrorWithAttrs] or [AddAttrs]). This protects log pipelines from call sites that
+// attach attributes in a loop. If an error is given more attributes than the limit, the first ones
+// are kept, and the rest are replaced by an attribute with the key [DroppedAttrsKey] and the number
+// of dropped attributes as its value.
+//
+// The default limit is [DefaultMaxAttrs]. Pass 0 (or a negative number) to remove the limit.
+func SetMaxAttrs(limit int) {
+	maxAttrs.Store(&limit)
+	attrsConfigVersion.Add(1)
+}
+
+// Parses the given attributes, and applies the attribute limit and the attribute sanitizer (if
+// set), for attributes that are stored on errors.
+func newAttrs(unparsed []any) []slog.Attr {
+	attrs := ParseAttrs(unparsed)
+
+	limit := DefaultMaxAttrs
+	if configured := maxAttrs.Load(); configured != nil {
+		limit = *configured
+	}
+	if limit > 0 && len(attrs) > limit {
+		dropped := len(attrs) - limit
+		attrs = append(attrs[:limit], slog.Int(DroppedAttrsKey, dropped))
+	}
+
+	if sanitizer := attrSanitizer.Load(); sanitizer != nil {
+		for i, attr := range attrs {
+			// Sanitizes the value inside debug attributes, so that they stay marked as debug
+			if value, isDebug := debugValueOf(attr.Value); isDebug {
+				attrs[i].Value = slog.AnyValue(debugValue{value: (*sanitizer)(value)})
+			} else {
+				attrs[i].Value = (*sanitizer)(attr.Value)
+			}
+		}
+	}
+
+	return attrs
+}
+
+// hasLogAttrs is implemented by errors that carry structured log attributes. This matches the
+// method that logging libraries such as [hermannm.dev/devlog/log] look for on errors.
+type hasLogAttrs = errschema.ErrorWithAttrs
+
+// Attrs returns the structured log attributes attached to the given error and all errors it wraps,
+// outermost first. Private attributes (see [AddPrivateAttrs]) are only included for the outermost
+// layer. Attributes are read from the LogAttrs method of each error in the chain, so this
+// includes attributes from errors created with [ErrorWithAttrs], as well as error types from other
+// packages that implement the same method. Attributes from leaf formatters registered with
+// [RegisterLeafFormatter] are also included.
+//
+// Since error chains don't change after they are created, the result is cached on the given error
+// when it was created by this package with attributes or a stack trace (e.g. by [ErrorWithAttrs],
+// [AddAttrs], [AddStack] or [Builder.Err]), so that logging the same error repeatedly doesn't merge
+// the attributes of a deep chain every time. The cache is invalidated by [RegisterLeafFormatter],
+// [SetMaxAttrs] and [SetAttrSanitizer]. The returned slice may be shared between calls, and must
+// not be modified (appending to it is safe, since its capacity is clipped).
+func Attrs(err error) []slog.Attr {
+	// Loaded before merging, so that a change during the merge invalidates the stored result
+	version := attrsConfigVersion.Load()
+
+	cacher, cacheable := err.(hasMergedAttrsCache)
+	if cacheable {
+		if cached := cacher.mergedAttrs().Load(); cached != nil && cached.version == version {
+			return cached.attrs
+		}
+	}
+
+	attrs := mergeAttrs(err)
+
+	if cacheable {
+		attrs = slices.Clip(attrs)
+		cacher.mergedAttrs().Store(&cachedAttrs{attrs: attrs, version: version})
+	}
+	return attrs
+}
+
+// Collects the attributes of the given error chain for Attrs, without caching.
+func mergeAttrs(err error) []slog.Attr {
+	var attrs []slog.Attr
+	outermost := true
+	forEachInChain(err, func(err error) {
+		if withAttrs, ok := err.(hasLogAttrs); ok && (outermost || !hasPrivateAttrs(err)) {
+			attrs = append(attrs, withAttrs.LogAttrs()...)
+		}
+		attrs = append(attrs, leafAttrs(err)...)
+
+		// Markers are part of the layer they wrap, so the outermost layer ends at the first
+		// non-marker error
+		if _, isMarker := err.(markerError); !isMarker {
+			outermost = false
+		}
+	})
+	return removeRepeatedRequestIDs(attrs)
+}
+
+// Calls the given function for the given error and every error it wraps (depth-first), following
+// both the single-error and multi-error Unwrap methods from the standard errors package.
+func forEachInChain(err error, fn func(err error)) {
+	for err != nil {
+		fn(err)
+
+		switch unwrappable := err.(type) {
+		case interface{ Unwrap() error }:
+			err = unwrappable.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, wrappedErr := range unwrappable.Unwrap() {
+				forEachInChain(wrappedErr, fn)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
+
+// Attaches log attributes to a wrapped error without adding a message. Used by AddAttrs, and by
+// Builder when attributes are given without a message.
+type attrsMarkerError struct {
+	wrapped error
+	attrs   []slog.Attr
+	private bool
+	mergedAttrsCache
+}
+
+func (err *attrsMarkerError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *attrsMarkerError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *attrsMarkerError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// LogAttrs returns the structured log attributes attached to this error (not including attributes
+// of the errors it wraps), for logging libraries that look for this method (such as
+// [hermannm.dev/devlog/log]).
+func (err *attrsMarkerError) LogAttrs() []slog.Attr {
+	return err.attrs
+}
+
+// Caches the result of Attrs on an error. Only errors that are created as pointers can hold the
+// cache, since it must not be copied.
+type mergedAttrsCache struct {
+	merged atomic.Pointer[cachedAttrs]
+}
+
+func (cache *mergedAttrsCache) mergedAttrs() *atomic.Pointer[cachedAttrs] {
+	return &cache.merged
+}
+
+type hasMergedAttrsCache interface {
+	mergedAttrs() *atomic.Pointer[cachedAttrs]
+}
+
+type cachedAttrs struct {
+	attrs []slog.Attr
+	// The value of attrsConfigVersion when the attributes were merged.
+	version uint64
+}
+
+// Incremented by the settings that may change the attributes of errors (RegisterLeafFormatter,
+// SetMaxAttrs and SetAttrSanitizer), so that results of Attrs cached before a change are not used
+// after it.
+var attrsConfigVersion atomic.Uint64
+
+// Returns true if the attributes of the given error were marked as private to its layer, with
+// AddPrivateAttrs or Builder.PrivateAttrs.
+func hasPrivateAttrs(err error) bool {
+	switch err := err.(type) {
+	case *errorWithAttrs:
+		return err.private
+	case *leafErrorWithAttrs:
+		return err.private
+	case *attrsMarkerError:
+		return err.private
+	default:
+		return false
+	}
+}
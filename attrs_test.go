@@ -0,0 +1,215 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestErrorWithAttrs(t *testing.T) {
+	err := errors.New("username already taken")
+	wrapped := wrap.ErrorWithAttrs(err, "failed to create user", "username", "hermannm")
+
+	expected := `failed to create user
+- username already taken`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrs(t, wrapped, []slog.Attr{slog.String("username", "hermannm")})
+
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to return true for error with attributes")
+	}
+}
+
+func TestNestedErrorWithAttrs(t *testing.T) {
+	err := errors.New("error")
+	inner := wrap.ErrorWithAttrs(err, "inner wrapped error", slog.Int("attempt", 3))
+	outer := wrap.Errors("outer wrapped errors", inner, errors.New("other error"))
+
+	expected := `outer wrapped errors
+- inner wrapped error
+  - error
+- other error`
+
+	assertEqualErrorStrings(t, outer, expected)
+}
+
+func TestNewErrorWithAttrs(t *testing.T) {
+	err := wrap.NewErrorWithAttrs("username too long", "username", "hermannm", "max_length", 5)
+	wrapped := wrap.Error(err, "invalid user")
+
+	expected := `invalid user
+- username too long`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrs(
+		t,
+		err,
+		[]slog.Attr{slog.String("username", "hermannm"), slog.Int("max_length", 5)},
+	)
+}
+
+func TestAddAttrs(t *testing.T) {
+	err := errors.New("user not found")
+	withAttrs := wrap.AddAttrs(err, "user_id", 123)
+	wrapped := wrap.ErrorWithAttrs(withAttrs, "failed to fetch user", "attempt", 2)
+
+	expected := `failed to fetch user
+- user not found`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrs(t, withAttrs, []slog.Attr{slog.Int("user_id", 123)})
+	assertEqualAttrSlices(
+		t,
+		wrap.Attrs(wrapped),
+		[]slog.Attr{slog.Int("attempt", 2), slog.Int("user_id", 123)},
+	)
+
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+	if wrap.AddAttrs(nil, "key", "value") != nil {
+		t.Error("expected AddAttrs to return nil for nil error")
+	}
+}
+
+func TestPrivateAttrs(t *testing.T) {
+	err := wrap.AddPrivateAttrs(errors.New("connection reset"), "tcp_state", "CLOSE_WAIT")
+	inner := wrap.New(err).Message("query failed").Attrs("sql", "SELECT 1").PrivateAttrs().Err()
+	outer := wrap.ErrorWithAttrs(inner, "failed to fetch user", "user_id", 123)
+
+	assertEqualAttrSlices(t, wrap.Attrs(outer), []slog.Attr{slog.Int("user_id", 123)})
+	assertEqualAttrSlices(t, wrap.Attrs(inner), []slog.Attr{slog.String("sql", "SELECT 1")})
+	assertEqualAttrSlices(
+		t,
+		wrap.Attrs(err),
+		[]slog.Attr{slog.String("tcp_state", "CLOSE_WAIT")},
+	)
+
+	layers := wrap.Flatten(outer)
+	assertEqualAttrSlices(t, layers[1].Attrs, []slog.Attr{slog.String("sql", "SELECT 1")})
+}
+
+func TestParseAttrs(t *testing.T) {
+	attrs := wrap.ParseAttrs([]any{"key1", "value1", slog.Int("key2", 2), 3, "key3"})
+
+	expected := []slog.Attr{
+		slog.String("key1", "value1"),
+		slog.Int("key2", 2),
+		slog.Int("!BADKEY", 3),
+		slog.String("!BADKEY", "key3"),
+	}
+
+	if len(attrs) != len(expected) {
+		t.Fatalf("unexpected number of attributes; got %v, want %v", attrs, expected)
+	}
+	for i := range attrs {
+		if !attrs[i].Equal(expected[i]) {
+			t.Errorf("unexpected attribute at index %d; got %v, want %v", i, attrs[i], expected[i])
+		}
+	}
+}
+
+func TestParseAttrsWithOnlyAttrs(t *testing.T) {
+	expected := []slog.Attr{slog.Int("key1", 1), slog.String("key2", "value2")}
+	assertEqualAttrSlices(t, wrap.ParseAttrs([]any{expected[0], expected[1]}), expected)
+}
+
+func TestAttrsCachedResultIsStable(t *testing.T) {
+	err := wrap.ErrorWithAttrs(errors.New("error"), "wrapped error", "key1", "value1")
+	expected := []slog.Attr{slog.String("key1", "value1")}
+
+	first := wrap.Attrs(err)
+	_ = append(first, slog.String("key2", "value2"))
+	assertEqualAttrSlices(t, wrap.Attrs(err), expected)
+
+	// The outer error is not created from the cached inner error, so private attributes must still
+	// be left out
+	private := wrap.AddPrivateAttrs(errors.New("error"), "private", true)
+	wrap.Attrs(private)
+	outer := wrap.ErrorWithAttrs(private, "wrapped error", "key1", "value1")
+	assertEqualAttrSlices(t, wrap.Attrs(outer), expected)
+}
+
+func BenchmarkAttrs(b *testing.B) {
+	err := errors.New("connection refused")
+	for i := 0; i < 10; i++ {
+		err = wrap.ErrorWithAttrs(err, "wrapped error", "layer", i, "table", "users")
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		wrap.Attrs(err)
+	}
+}
+
+func BenchmarkParseAttrs(b *testing.B) {
+	b.Run("KeyValuePairs", func(b *testing.B) {
+		unparsed := []any{"user_id", 123, "username", "hermannm", "admin", true, "score", 1.5}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			wrap.ParseAttrs(unparsed)
+		}
+	})
+	b.Run("Attrs", func(b *testing.B) {
+		unparsed := []any{
+			slog.Int("user_id", 123),
+			slog.String("username", "hermannm"),
+			slog.Bool("admin", true),
+			slog.Float64("score", 1.5),
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			wrap.ParseAttrs(unparsed)
+		}
+	})
+}
+
+func assertEqualAttrs(t *testing.T, err error, expected []slog.Attr) {
+	t.Helper()
+
+	withAttrs, ok := err.(interface{ LogAttrs() []slog.Attr })
+	if !ok {
+		t.Fatalf("expected error to implement LogAttrs, got %T", err)
+	}
+
+	assertEqualAttrSlices(t, withAttrs.LogAttrs(), expected)
+}
+
+func assertEqualAttrSlices(t *testing.T, actual []slog.Attr, expected []slog.Attr) {
+	t.Helper()
+
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected attributes; got %v, want %v", actual, expected)
+	}
+	for i := range actual {
+		if !actual[i].Equal(expected[i]) {
+			t.Errorf("unexpected attribute at index %d; got %v, want %v", i, actual[i], expected[i])
+		}
+	}
+}
+
+func TestSetMaxAttrs(t *testing.T) {
+	wrap.SetMaxAttrs(2)
+	defer wrap.SetMaxAttrs(wrap.DefaultMaxAttrs)
+
+	var attrs []any
+	for i := 0; i < 5; i++ {
+		attrs = append(attrs, "key", i)
+	}
+	err := wrap.ErrorWithAttrs(errors.New("error"), "wrapped error", attrs...)
+
+	assertEqualAttrs(t, err, []slog.Attr{
+		slog.Int("key", 0),
+		slog.Int("key", 1),
+		slog.Int("dropped_attrs", 3),
+	})
+
+	wrap.SetMaxAttrs(0)
+	err = wrap.AddAttrs(errors.New("error"), attrs...)
+	if count := len(wrap.Attrs(err)); count != 5 {
+		t.Errorf("expected all 5 attributes to be kept without limit, got %d", count)
+	}
+}
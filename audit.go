@@ -0,0 +1,77 @@
+package wrap
+
+import (
+	"strings"
+)
+
+// Log attribute keys that AuditEventOf reads the actor, action and target of audit events from.
+const (
+	AuditActorKey  = "actor"
+	AuditActionKey = "action"
+	AuditTargetKey = "target"
+)
+
+// AuditOutcomeFailure is the outcome of audit events created by [AuditEventOf].
+const AuditOutcomeFailure = "failure"
+
+// AuditEvent is an audit log event for a failed operation (see [AuditEventOf]).
+type AuditEvent struct {
+	// Actor is the user or service that attempted the operation, e.g. "user:123".
+	Actor string `json:"actor"`
+	// Action is the attempted operation, e.g. "invoice.delete".
+	Action string `json:"action"`
+	// Target is the resource that the operation was attempted on, e.g. "invoice:456".
+	Target string `json:"target"`
+	// Outcome is always [AuditOutcomeFailure], since the event was created from an error.
+	Outcome string `json:"outcome"`
+	// Reason is the messages of the error's layers, joined with ": " (see [Messages]).
+	Reason string `json:"reason"`
+	// Code is the error code attached to the error (see [CodeOf]), if any.
+	Code string `json:"code,omitempty"`
+}
+
+// AuditEventOf converts the given error to an audit log event, so that security-relevant failures
+// can be sent to an audit pipeline with a consistent structure. The actor, action and target of the
+// event are read from the attributes with the keys [AuditActorKey], [AuditActionKey] and
+// [AuditTargetKey] on the error and the errors it wraps (see [Attrs]). If an attribute is set on
+// several layers, the outermost one is used.
+//
+// Example:
+//
+//	err := wrap.ErrorWithAttrs(
+//		errors.New("permission denied"),
+//		"failed to delete invoice",
+//		wrap.AuditActorKey, "user:123",
+//		wrap.AuditActionKey, "invoice.delete",
+//		wrap.AuditTargetKey, "invoice:456",
+//	)
+//	event := wrap.AuditEventOf(err)
+//	fmt.Println(event.Actor, event.Outcome, event.Reason)
+//	// user:123 failure failed to delete invoice: permission denied
+func AuditEventOf(err error) AuditEvent {
+	event := AuditEvent{
+		Outcome: AuditOutcomeFailure,
+		Reason:  strings.Join(Messages(err), ": "),
+	}
+	event.Code, _ = CodeOf(err)
+
+	for _, attr := range Attrs(err) {
+		var field *string
+		switch attr.Key {
+		case AuditActorKey:
+			field = &event.Actor
+		case AuditActionKey:
+			field = &event.Action
+		case AuditTargetKey:
+			field = &event.Target
+		default:
+			continue
+		}
+
+		if *field == "" {
+			*field = attr.Value.Resolve().String()
+		}
+	}
+
+	return event
+}
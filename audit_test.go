@@ -0,0 +1,44 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAuditEventOf(t *testing.T) {
+	inner := wrap.ErrorWithAttrs(
+		errors.New("permission denied"),
+		"failed to delete invoice",
+		wrap.AuditActionKey, "invoice.delete",
+		wrap.AuditTargetKey, "invoice:456",
+		wrap.AuditActorKey, "service:billing",
+	)
+	err := wrap.ErrorWithCode(
+		wrap.ErrorWithAttrs(inner, "handler failed", wrap.AuditActorKey, "user:123"),
+		"FORBIDDEN",
+		"request denied",
+	)
+
+	event := wrap.AuditEventOf(err)
+	expected := wrap.AuditEvent{
+		Actor:   "user:123",
+		Action:  "invoice.delete",
+		Target:  "invoice:456",
+		Outcome: wrap.AuditOutcomeFailure,
+		Reason:  "request denied: handler failed: failed to delete invoice: permission denied",
+		Code:    "FORBIDDEN",
+	}
+	if event != expected {
+		t.Errorf("unexpected audit event\nwant: %+v\n got: %+v", expected, event)
+	}
+}
+
+func TestAuditEventOfWithoutAttrs(t *testing.T) {
+	event := wrap.AuditEventOf(errors.New("permission denied"))
+	expected := wrap.AuditEvent{Outcome: wrap.AuditOutcomeFailure, Reason: "permission denied"}
+	if event != expected {
+		t.Errorf("unexpected audit event\nwant: %+v\n got: %+v", expected, event)
+	}
+}
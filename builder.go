@@ -0,0 +1,204 @@
+package wrap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// New starts building an error that wraps the given error, for combining several facets (a message,
+// log attributes, an error code, a stack trace) in one expression, instead of nesting wrapping
+// functions:
+//
+//	err := wrap.New(err).
+//		Message("failed to insert user").
+//		Attrs("table", "users").
+//		Code("DB_CONFLICT").
+//		Stack().
+//		Err()
+//
+// Each method sets a facet and returns the builder, and [Builder.Err] creates the error. If the
+// given error is nil, the builder creates a new root error with the message instead.
+func New(wrapped error) *Builder {
+	return &Builder{wrapped: wrapped}
+}
+
+// Builder builds an error from facets. Create it with [New].
+type Builder struct {
+	wrapped       error
+	message       string
+	messageFormat string
+	attrs         []any
+	privateAttrs  bool
+	code          string
+	level         *slog.Level
+	retryable     bool
+	ctx           context.Context
+	stack         StackTrace
+	// Set by WithStack, to capture the stack trace in Err.
+	captureStack bool
+	// Set by WithErrorID, to stamp the error with an ID in Err.
+	errorID bool
+}
+
+// Message sets the message to wrap the error with (like [Error]). If no message is set, the facets
+// are attached to the wrapped error without adding a line to the error string.
+func (builder *Builder) Message(message string) *Builder {
+	builder.message = message
+	builder.messageFormat = ""
+	return builder
+}
+
+// Messagef sets the message to wrap the error with, by forwarding the given message format and
+// args to [fmt.Sprintf] (like [Errorf]).
+func (builder *Builder) Messagef(messageFormat string, formatArgs ...any) *Builder {
+	builder.message = fmt.Sprintf(messageFormat, formatArgs...)
+	builder.messageFormat = messageFormat
+	return builder
+}
+
+// Attrs adds structured log attributes to the error (on the same format as [ErrorWithAttrs]). It
+// can be called multiple times to add more attributes.
+func (builder *Builder) Attrs(attrs ...any) *Builder {
+	builder.attrs = append(builder.attrs, attrs...)
+	return builder
+}
+
+// PrivateAttrs marks the attributes of the error as private to its layer, so that they are not
+// inherited by errors that wrap it (see [AddPrivateAttrs]).
+func (builder *Builder) PrivateAttrs() *Builder {
+	builder.privateAttrs = true
+	return builder
+}
+
+// Code attaches a machine-readable error code to the error (like [ErrorWithCode]).
+func (builder *Builder) Code(code string) *Builder {
+	builder.code = code
+	return builder
+}
+
+// Level attaches a log severity level to the error (like [ErrorWithLevel]).
+func (builder *Builder) Level(level slog.Level) *Builder {
+	builder.level = &level
+	return builder
+}
+
+// Retryable marks the error as retryable (like [Retryable]).
+func (builder *Builder) Retryable() *Builder {
+	builder.retryable = true
+	return builder
+}
+
+// Context attaches the given context to the error, for logging libraries that extract context
+// values (such as trace IDs) when the error is logged. The context is available through the Context
+// method of the error's layer. If the context is flagged with [WithVerboseErrors], the error also
+// captures a stack trace (like [WithStack]).
+//
+// Only the context itself is attached. Unlike the errors from [hermannm.dev/wrap/ctxwrap], the
+// error does not include the context's request ID, the attributes of its cancel cause or the
+// missing and done context tags, and the context is stored as is, even if snapshotting is enabled
+// (see [hermannm.dev/wrap/ctxwrap.SetSnapshotContexts]). Use ctxwrap for errors that need those.
+func (builder *Builder) Context(ctx context.Context) *Builder {
+	builder.ctx = ctx
+	return builder
+}
+
+// Stack attaches a stack trace of the caller to the error (like [AddStack]).
+func (builder *Builder) Stack() *Builder {
+	builder.stack = CaptureStack(1)
+	return builder
+}
+
+// Err creates the error from the facets set on the builder. It returns nil if neither a wrapped
+// error nor a message was given.
+func (builder *Builder) Err() error {
+	var err error
+	switch {
+	case builder.wrapped != nil && builder.message != "":
+		err = &errorWithAttrs{
+			wrappedError: wrappedError{
+				wrapped:       builder.wrapped,
+				message:       builder.message,
+				messageFormat: builder.messageFormat,
+				caller:        recordCaller(),
+			},
+			attrs:   newAttrs(builder.attrs),
+			private: builder.privateAttrs,
+		}
+	case builder.wrapped != nil:
+		err = builder.wrapped
+		if len(builder.attrs) != 0 {
+			err = &attrsMarkerError{
+				wrapped: err,
+				attrs:   newAttrs(builder.attrs),
+				private: builder.privateAttrs,
+			}
+		}
+	case builder.message != "":
+		err = &leafErrorWithAttrs{
+			message: builder.message,
+			attrs:   newAttrs(builder.attrs),
+			private: builder.privateAttrs,
+		}
+	default:
+		return nil
+	}
+
+	if builder.ctx != nil {
+		err = &contextMarkerError{wrapped: err, ctx: builder.ctx}
+	}
+	if builder.code != "" {
+		err = &codeError{wrapped: err, code: builder.code}
+	}
+	if builder.retryable {
+		err = &retryableError{wrapped: err}
+	}
+	if builder.errorID {
+		err = AddErrorID(err)
+	}
+	stack := builder.stack
+	if stack == nil && builder.shouldCaptureStack() {
+		stack = CaptureStack(1)
+	}
+	if stack != nil {
+		err = &stackError{wrapped: err, stack: stack}
+	}
+	// The level marker goes outermost, so that logging libraries checking the Level method of
+	// the returned error find it
+	if builder.level != nil {
+		err = &levelError{wrapped: err, level: *builder.level}
+	}
+
+	return runWrapHook(withNetErrorFrom(err, builder.wrapped))
+}
+
+// Returns whether the error should capture a stack trace when it is created, because of WithStack
+// or a context flagged with WithVerboseErrors.
+func (builder *Builder) shouldCaptureStack() bool {
+	return builder.captureStack || (builder.ctx != nil && VerboseErrors(builder.ctx))
+}
+
+// Attaches a context to a wrapped error, as a marker around the layer it applies to.
+type contextMarkerError struct {
+	wrapped error
+	ctx     context.Context
+}
+
+func (err *contextMarkerError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *contextMarkerError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *contextMarkerError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// Context returns the context attached to the error, for logging libraries that look for this
+// method (such as [hermannm.dev/devlog/log]).
+func (err *contextMarkerError) Context() context.Context {
+	return err.ctx
+}
@@ -0,0 +1,71 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestBuilder(t *testing.T) {
+	err := errors.New("duplicate key")
+	wrapped := wrap.New(err).
+		Messagef("failed to insert %s", "user").
+		Attrs("table", "users").
+		Attrs(slog.Int("attempt", 2)).
+		Code("DB_CONFLICT").
+		Stack().
+		Err()
+
+	expected := `failed to insert user
+- duplicate key`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrSlices(
+		t,
+		wrap.Attrs(wrapped),
+		[]slog.Attr{
+			slog.String("code", "DB_CONFLICT"),
+			slog.String("table", "users"),
+			slog.Int("attempt", 2),
+		},
+	)
+
+	if code, _ := wrap.CodeOf(wrapped); code != "DB_CONFLICT" {
+		t.Errorf("unexpected code %q", code)
+	}
+
+	stack, ok := wrap.Stack(wrapped)
+	if !ok {
+		t.Fatal("expected error to have a stack trace")
+	}
+	if frames := stack.Frames(); !strings.HasSuffix(frames[0].Function, "TestBuilder") {
+		t.Errorf("expected stack trace to start at the caller, got %s", frames[0].Function)
+	}
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+}
+
+func TestBuilderWithoutMessage(t *testing.T) {
+	err := wrap.Error(errors.New("error"), "wrapped error")
+	withAttrs := wrap.New(err).Attrs("key", "value").Err()
+
+	assertEqualErrorStrings(t, wrap.Error(withAttrs, "outer error"), `outer error
+- wrapped error
+- error`)
+	assertEqualAttrSlices(t, wrap.Attrs(withAttrs), []slog.Attr{slog.String("key", "value")})
+}
+
+func TestBuilderWithoutWrappedError(t *testing.T) {
+	err := wrap.New(nil).Message("quota exceeded").Attrs("limit", 10).Err()
+
+	assertEqualErrorStrings(t, err, "quota exceeded")
+	assertEqualAttrs(t, err, []slog.Attr{slog.Int("limit", 10)})
+
+	if err := wrap.New(nil).Attrs("key", "value").Err(); err != nil {
+		t.Errorf("expected nil error without wrapped error or message, got %v", err)
+	}
+}
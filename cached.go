@@ -0,0 +1,58 @@
+package wrap
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Cached marks the given error as a cached result, originally produced at the given time. Use it
+// when replaying memoized failures (such as a cached "user not found" lookup), so that handlers and
+// logs can tell replayed failures apart from fresh ones.
+//
+// The marker does not change how the error is displayed:
+//
+//	err := errors.New("user not found")
+//	cached := wrap.Cached(err, time.Now())
+//	fmt.Println(cached)
+//	// user not found
+//
+// Use [CachedAt] to check whether an error was cached. The returned error implements the Unwrap
+// method from the standard errors package, so it works with [errors.Is] and [errors.As].
+func Cached(wrapped error, at time.Time) error {
+	return withNetError(&cachedError{wrapped: wrapped, cachedAt: at})
+}
+
+// CachedAt returns the time at which the given error was cached, if it or any error it wraps was
+// marked with [Cached]. If there are several cached markers in the chain, the outermost one is used.
+func CachedAt(err error) (cachedAt time.Time, ok bool) {
+	var cached *cachedError
+	if errors.As(err, &cached) {
+		return cached.cachedAt, true
+	}
+	return time.Time{}, false
+}
+
+type cachedError struct {
+	wrapped  error
+	cachedAt time.Time
+}
+
+func (err *cachedError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *cachedError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *cachedError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// LogAttrs returns structured log attributes for the cached marker, for logging libraries that look
+// for this method (such as [hermannm.dev/devlog/log]).
+func (err *cachedError) LogAttrs() []slog.Attr {
+	return []slog.Attr{slog.Bool("cached", true), slog.Time("cached_at", err.cachedAt)}
+}
@@ -0,0 +1,57 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+func TestCached(t *testing.T) {
+	cachedAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	err := errors.New("user not found")
+	inner := wrap.Error(err, "user lookup failed")
+	cached := wrap.Cached(inner, cachedAt)
+	outer := wrap.Error(cached, "failed to load profile")
+
+	expected := `failed to load profile
+- user lookup failed
+- user not found`
+
+	assertEqualErrorStrings(t, outer, expected)
+
+	actual, ok := wrap.CachedAt(outer)
+	if !ok {
+		t.Fatal("expected CachedAt to find cached marker")
+	}
+	if !actual.Equal(cachedAt) {
+		t.Errorf("unexpected cached time; got %v, want %v", actual, cachedAt)
+	}
+
+	if !errors.Is(outer, err) {
+		t.Error("expected errors.Is to return true for cached error")
+	}
+}
+
+func TestCachedInList(t *testing.T) {
+	err1 := wrap.Errors("inner wrapped errors", errors.New("error 1"), errors.New("error 2"))
+	err2 := errors.New("error 3")
+	wrapped := wrap.Errors("outer wrapped errors", wrap.Cached(err1, time.Now()), err2)
+
+	expected := `outer wrapped errors
+- inner wrapped errors
+  - error 1
+  - error 2
+- error 3`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
+
+func TestNotCached(t *testing.T) {
+	wrapped := wrap.Error(errors.New("error"), "wrapped error")
+	if _, ok := wrap.CachedAt(wrapped); ok {
+		t.Error("expected CachedAt to return false for error without cached marker")
+	}
+}
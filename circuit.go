@@ -0,0 +1,29 @@
+package wrap
+
+import (
+	"errors"
+)
+
+// ErrCircuitOpen is the sentinel error for calls that were rejected by an open circuit breaker,
+// without ever reaching the upstream service. Client wrappers should return it (typically through
+// [CircuitOpen]) instead of a generic error, so that callers can tell short-circuited calls apart
+// from real upstream failures.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpen wraps [ErrCircuitOpen] with a message for context, for use by client wrappers when a
+// call is short-circuited.
+//
+// Example:
+//
+//	wrapped := wrap.CircuitOpen("request to payment service rejected")
+//	fmt.Println(wrapped)
+//	// request to payment service rejected
+//	// - circuit breaker is open
+func CircuitOpen(message string) error {
+	return Error(ErrCircuitOpen, message)
+}
+
+// IsCircuitOpen checks whether the given error or any error it wraps is [ErrCircuitOpen].
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
@@ -0,0 +1,30 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestCircuitOpen(t *testing.T) {
+	err := wrap.CircuitOpen("request to payment service rejected")
+	wrapped := wrap.Error(err, "failed to charge customer")
+
+	expected := `failed to charge customer
+- request to payment service rejected
+- circuit breaker is open`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if !wrap.IsCircuitOpen(wrapped) {
+		t.Error("expected IsCircuitOpen to return true for wrapped circuit breaker error")
+	}
+}
+
+func TestNotCircuitOpen(t *testing.T) {
+	wrapped := wrap.Error(errors.New("connection refused"), "request to payment service failed")
+	if wrap.IsCircuitOpen(wrapped) {
+		t.Error("expected IsCircuitOpen to return false for upstream failure")
+	}
+}
@@ -0,0 +1,154 @@
+package wrap
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Classification groups a set of observed errors into classes of similar errors, as returned by
+// [Report]. Render it with [Classification.String] (plain text) or [Classification.Markdown].
+type Classification struct {
+	// Total is the number of (non-nil) errors that were classified.
+	Total int `json:"total"`
+	// Classes are the groups of similar errors, the most frequent first.
+	Classes []ErrorClass `json:"classes"`
+}
+
+// ErrorClass is a group of similar errors in a [Classification].
+type ErrorClass struct {
+	// Count is the number of errors in the class.
+	Count int `json:"count"`
+	// Code is the error code of the errors (see [CodeOf]), or "" if they have none.
+	Code string `json:"code,omitempty"`
+	// RootCauseTypes are the Go types of the root causes of the errors (see [LeafTypes]).
+	RootCauseTypes []string `json:"root_cause_types"`
+	// Fingerprint is the fingerprint of the errors (see [Fingerprint]).
+	Fingerprint string `json:"fingerprint"`
+	// Example is the first error of the class.
+	Example error `json:"-"`
+}
+
+// Report classifies the given errors (e.g. errors collected during a load test or an incident), for
+// post-mortems and incident write-ups. Errors are grouped by their fingerprint and code, so errors
+// in the same class have the same wrapping messages, code and root causes (see [Fingerprint]).
+// Classes are ordered by count, with ties in the order their first errors appeared. Nil errors are
+// ignored.
+//
+// Example:
+//
+//	classification := wrap.Report(loadTestErrors)
+//	fmt.Println(classification.Markdown())
+func Report(errs []error) Classification {
+	type classKey struct {
+		fingerprint string
+		code        string
+	}
+
+	var classification Classification
+	classIndexes := make(map[classKey]int)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		classification.Total++
+
+		code, _ := CodeOf(err)
+		key := classKey{fingerprint: Fingerprint(err), code: code}
+		if index, ok := classIndexes[key]; ok {
+			classification.Classes[index].Count++
+			continue
+		}
+
+		classIndexes[key] = len(classification.Classes)
+		classification.Classes = append(classification.Classes, ErrorClass{
+			Count:          1,
+			Code:           code,
+			RootCauseTypes: LeafTypes(err),
+			Fingerprint:    key.fingerprint,
+			Example:        err,
+		})
+	}
+
+	slices.SortStableFunc(classification.Classes, func(class1, class2 ErrorClass) int {
+		return cmp.Compare(class2.Count, class1.Count)
+	})
+	return classification
+}
+
+// String renders the classification as a plain-text table, with one row per class:
+//
+//	5 errors in 2 classes
+//	COUNT  SHARE  CODE     ROOT CAUSE            FINGERPRINT       MESSAGE
+//	3      60%    TIMEOUT  *net.OpError          8f3a2c1b9d0e4f56  failed to fetch user
+//	2      40%    -        *errors.errorString   1b2c3d4e5f607182  failed to parse request
+func (classification Classification) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s\n", classification.title())
+
+	table := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "COUNT\tSHARE\tCODE\tROOT CAUSE\tFINGERPRINT\tMESSAGE")
+	for _, class := range classification.Classes {
+		fmt.Fprintf(table, "%s\n", strings.Join(classification.row(class), "\t"))
+	}
+	table.Flush()
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// Markdown renders the classification as a Markdown table, with one row per class, for pasting
+// into incident write-ups.
+func (classification Classification) Markdown() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s\n\n", classification.title())
+	builder.WriteString("| Count | Share | Code | Root cause | Fingerprint | Message |\n")
+	builder.WriteString("| ---: | ---: | --- | --- | --- | --- |\n")
+	for _, class := range classification.Classes {
+		row := classification.row(class)
+		for i, cell := range row {
+			row[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		fmt.Fprintf(&builder, "| %s |\n", strings.Join(row, " | "))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func (classification Classification) title() string {
+	return fmt.Sprintf(
+		"%d %s in %d %s",
+		classification.Total,
+		pluralize(classification.Total, "error", "errors"),
+		len(classification.Classes),
+		pluralize(len(classification.Classes), "class", "classes"),
+	)
+}
+
+// Returns the cells of the table row for the given class, with the outermost message of the
+// class's example error (the first line of its error string).
+func (classification Classification) row(class ErrorClass) []string {
+	code := class.Code
+	if code == "" {
+		code = "-"
+	}
+	message, _, _ := strings.Cut(class.Example.Error(), "\n")
+	rootCauseTypes := slices.Clone(class.RootCauseTypes)
+	slices.Sort(rootCauseTypes)
+
+	return []string{
+		fmt.Sprint(class.Count),
+		fmt.Sprintf("%d%%", class.Count*100/classification.Total),
+		code,
+		strings.Join(slices.Compact(rootCauseTypes), ", "),
+		class.Fingerprint,
+		message,
+	}
+}
+
+func pluralize(count int, singular string, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
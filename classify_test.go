@@ -0,0 +1,69 @@
+package wrap_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestReport(t *testing.T) {
+	timeout := errors.New("i/o timeout")
+	invalid := errors.New("invalid email")
+	timeoutErr := func() error {
+		return wrap.ErrorWithCode(timeout, "TIMEOUT", "failed to fetch user")
+	}
+	invalidErr := wrap.Error(invalid, "failed to parse request|response")
+
+	classification := wrap.Report([]error{
+		invalidErr,
+		timeoutErr(),
+		nil,
+		timeoutErr(),
+		timeoutErr(),
+		invalidErr,
+	})
+
+	if classification.Total != 5 || len(classification.Classes) != 2 {
+		t.Fatalf("unexpected classification: %+v", classification)
+	}
+	first := classification.Classes[0]
+	if first.Count != 3 || first.Code != "TIMEOUT" ||
+		first.RootCauseTypes[0] != "*errors.errorString" {
+		t.Errorf("unexpected most frequent class: %+v", first)
+	}
+
+	expectedMarkdown := fmt.Sprintf(
+		`5 errors in 2 classes
+
+| Count | Share | Code | Root cause | Fingerprint | Message |
+| ---: | ---: | --- | --- | --- | --- |
+| 3 | 60%% | TIMEOUT | *errors.errorString | %s | failed to fetch user |
+| 2 | 40%% | - | *errors.errorString | %s | failed to parse request\|response |`,
+		wrap.Fingerprint(timeoutErr()),
+		wrap.Fingerprint(invalidErr),
+	)
+	if markdown := classification.Markdown(); markdown != expectedMarkdown {
+		t.Errorf("unexpected Markdown\nwant:\n%s\n\ngot:\n%s", expectedMarkdown, markdown)
+	}
+
+	text := classification.String()
+	lines := strings.Split(text, "\n")
+	if len(lines) != 4 || lines[0] != "5 errors in 2 classes" ||
+		!strings.HasPrefix(lines[1], "COUNT  SHARE  CODE") ||
+		!strings.HasPrefix(lines[2], "3      60%    TIMEOUT") {
+		t.Errorf("unexpected text table:\n%s", text)
+	}
+}
+
+func TestReportEmpty(t *testing.T) {
+	classification := wrap.Report([]error{nil})
+	if classification.Total != 0 || len(classification.Classes) != 0 {
+		t.Errorf("expected empty classification, got %+v", classification)
+	}
+	if text := classification.String(); !strings.HasPrefix(text, "0 errors in 0 classes") {
+		t.Errorf("unexpected text for empty classification: %q", text)
+	}
+}
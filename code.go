@@ -0,0 +1,78 @@
+package wrap
+
+import (
+	"log/slog"
+)
+
+// CodeKey is the log attribute key for error codes attached with [ErrorWithCode].
+const CodeKey = "code"
+
+// ErrorWithCode wraps the given error with a message for context, and attaches the given
+// machine-readable error code to it (e.g. "USER_NOT_FOUND"). Codes are meant for classifying errors
+// in API responses and metrics, while the message stays human-readable. The code is not included in
+// the error string, but is available through [CodeOf], and is logged with the key [CodeKey].
+//
+// Example:
+//
+//	err := errors.New("no rows in result set")
+//	wrapped := wrap.ErrorWithCode(err, "USER_NOT_FOUND", "failed to fetch user")
+//	fmt.Println(wrapped)
+//	// failed to fetch user
+//	// - no rows in result set
+//
+// The returned error implements the Unwrap method from the standard errors package, so it works
+// with [errors.Is] and [errors.As].
+func ErrorWithCode(wrapped error, code string, message string) error {
+	err := &codeError{
+		wrapped: &wrappedError{wrapped: wrapped, message: message, caller: recordCaller()},
+		code:    code,
+	}
+	return runWrapHook(withNetErrorFrom(err, wrapped))
+}
+
+// CodeOf returns the code attached to the given error or the errors it wraps with [ErrorWithCode]
+// (or the code of a [Sentinel] in the chain), if any. If there are several codes in the chain, the
+// outermost one is returned, since it is the most specific classification of the error as a whole.
+func CodeOf(err error) (code string, ok bool) {
+	forEachInChain(err, func(err error) {
+		if codedErr, isCoded := err.(codedError); isCoded && !ok {
+			code, ok = codedErr.errorCode()
+		}
+	})
+	return code, ok
+}
+
+// codedError is implemented by errors that may carry an error code, for CodeOf.
+type codedError interface {
+	errorCode() (code string, ok bool)
+}
+
+// Attaches a code to a wrapped error. The code is attached as a marker around the layer that it
+// classifies, so it doesn't change how the error is formatted.
+type codeError struct {
+	wrapped error
+	code    string
+}
+
+func (err *codeError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *codeError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *codeError) unwrapMarker() error {
+	return err.wrapped
+}
+
+func (err *codeError) errorCode() (code string, ok bool) {
+	return err.code, true
+}
+
+// LogAttrs returns the error code as a structured log attribute, for logging libraries that look
+// for this method (such as [hermannm.dev/devlog/log]).
+func (err *codeError) LogAttrs() []slog.Attr {
+	return []slog.Attr{slog.String(CodeKey, err.code)}
+}
@@ -0,0 +1,42 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestErrorWithCode(t *testing.T) {
+	err := errors.New("no rows in result set")
+	inner := wrap.ErrorWithCode(err, "USER_NOT_FOUND", "failed to fetch user")
+	outer := wrap.Error(inner, "failed to update username")
+
+	expected := `failed to update username
+- failed to fetch user
+- no rows in result set`
+
+	assertEqualErrorStrings(t, outer, expected)
+	assertEqualAttrSlices(t, wrap.Attrs(outer), []slog.Attr{slog.String("code", "USER_NOT_FOUND")})
+
+	code, ok := wrap.CodeOf(outer)
+	if !ok || code != "USER_NOT_FOUND" {
+		t.Errorf("unexpected code; got (%q, %t)", code, ok)
+	}
+	if !errors.Is(outer, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+}
+
+func TestCodeOfReturnsOutermost(t *testing.T) {
+	inner := wrap.ErrorWithCode(errors.New("error"), "INNER", "inner wrapped error")
+	outer := wrap.ErrorWithCode(inner, "OUTER", "outer wrapped error")
+
+	if code, _ := wrap.CodeOf(outer); code != "OUTER" {
+		t.Errorf("expected outermost code, got %q", code)
+	}
+	if _, ok := wrap.CodeOf(errors.New("error")); ok {
+		t.Error("expected CodeOf to return false for error without code")
+	}
+}
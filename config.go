@@ -0,0 +1,106 @@
+package wrap
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// Config holds the global settings of this package, for configuring them in one place with
+// [Configure] instead of calling each Set function. The zero value is the default configuration.
+//
+// Each setting that can be expressed as text can also be read from an environment variable with
+// [ConfigFromEnv], so that operations can tune behavior per environment without code changes.
+type Config struct {
+	// WidthAwareFormatting is the setting for [SetWidthAwareFormatting].
+	// Environment variable: WRAP_WIDTH_AWARE_FORMATTING (bool).
+	WidthAwareFormatting bool
+	// CollapseDuplicateMessages is the setting for [SetCollapseDuplicateMessages].
+	// Environment variable: WRAP_COLLAPSE_DUPLICATE_MESSAGES (bool).
+	CollapseDuplicateMessages bool
+	// CollapseDuplicateErrors is the setting for [SetCollapseDuplicateErrors].
+	// Environment variable: WRAP_COLLAPSE_DUPLICATE_ERRORS (bool).
+	CollapseDuplicateErrors bool
+	// RecordCallers is the setting for [SetRecordCallers].
+	// Environment variable: WRAP_RECORD_CALLERS (bool).
+	RecordCallers bool
+	// RecordCreationTimes is the setting for [SetRecordCreationTimes].
+	// Environment variable: WRAP_RECORD_CREATION_TIMES (bool).
+	RecordCreationTimes bool
+	// MaxAttrs is the limit for [SetMaxAttrs]. Unlike in SetMaxAttrs, 0 means [DefaultMaxAttrs],
+	// so that the zero value is the default. Set it to a negative number to remove the limit.
+	// Environment variable: WRAP_MAX_ATTRS (int).
+	MaxAttrs int
+	// AttrSanitizer is the hook for [SetAttrSanitizer], or nil for no sanitizer.
+	// Environment variable: WRAP_NORMALIZE_ATTR_VALUES (bool), which sets [NormalizeAttrValue].
+	AttrSanitizer func(value slog.Value) slog.Value
+	// WrapHook is the hook for [SetWrapHook], or nil for no hook. It has no environment variable.
+	WrapHook func(err error)
+}
+
+// Configure applies the given configuration, replacing every global setting of this package (see
+// [Config]). It is safe to call concurrently, but is typically called at program startup:
+//
+//	config, err := wrap.ConfigFromEnv()
+//	if err != nil {
+//		slog.Warn("Invalid error configuration", "error", err)
+//	}
+//	config.WrapHook = wrap.SizeWarningHook(64*1024, reportLargeError)
+//	wrap.Configure(config)
+func Configure(config Config) {
+	SetWidthAwareFormatting(config.WidthAwareFormatting)
+	SetCollapseDuplicateMessages(config.CollapseDuplicateMessages)
+	SetCollapseDuplicateErrors(config.CollapseDuplicateErrors)
+	SetRecordCallers(config.RecordCallers)
+	SetRecordCreationTimes(config.RecordCreationTimes)
+	if config.MaxAttrs == 0 {
+		maxAttrs.Store(nil)
+	} else {
+		SetMaxAttrs(config.MaxAttrs)
+	}
+	SetAttrSanitizer(config.AttrSanitizer)
+	SetWrapHook(config.WrapHook)
+}
+
+// ConfigFromEnv reads a configuration from the environment variables listed on the fields of
+// [Config]. Unset variables leave their setting at the default. If a variable fails to parse, its
+// setting is left at the default, and an error listing the invalid variables is returned along
+// with the rest of the configuration.
+func ConfigFromEnv() (Config, error) {
+	var config Config
+	var errs []error
+
+	parseBool := func(name string, setting *bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid boolean '%s' in %s", value, name))
+				return
+			}
+			*setting = parsed
+		}
+	}
+
+	parseBool("WRAP_WIDTH_AWARE_FORMATTING", &config.WidthAwareFormatting)
+	parseBool("WRAP_COLLAPSE_DUPLICATE_MESSAGES", &config.CollapseDuplicateMessages)
+	parseBool("WRAP_COLLAPSE_DUPLICATE_ERRORS", &config.CollapseDuplicateErrors)
+	parseBool("WRAP_RECORD_CALLERS", &config.RecordCallers)
+	parseBool("WRAP_RECORD_CREATION_TIMES", &config.RecordCreationTimes)
+
+	if value, ok := os.LookupEnv("WRAP_MAX_ATTRS"); ok {
+		if parsed, err := strconv.Atoi(value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid integer '%s' in WRAP_MAX_ATTRS", value))
+		} else {
+			config.MaxAttrs = parsed
+		}
+	}
+
+	var normalize bool
+	parseBool("WRAP_NORMALIZE_ATTR_VALUES", &normalize)
+	if normalize {
+		config.AttrSanitizer = NormalizeAttrValue
+	}
+
+	return config, ErrorsIf("invalid error configuration in environment", errs...)
+}
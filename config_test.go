@@ -0,0 +1,56 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("WRAP_COLLAPSE_DUPLICATE_MESSAGES", "true")
+	t.Setenv("WRAP_MAX_ATTRS", "2")
+	t.Setenv("WRAP_NORMALIZE_ATTR_VALUES", "1")
+
+	config, err := wrap.ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.CollapseDuplicateMessages || config.MaxAttrs != 2 || config.AttrSanitizer == nil {
+		t.Errorf("unexpected config %+v", config)
+	}
+	if config.RecordCallers || config.WidthAwareFormatting {
+		t.Errorf("expected unset variables to have default settings, got %+v", config)
+	}
+
+	wrap.Configure(config)
+	defer wrap.Configure(wrap.Config{})
+
+	err = wrap.Error(wrap.Error(errors.New("error"), "wrapped error"), "wrapped error")
+	assertEqualErrorStrings(t, err, "wrapped error\n- error")
+
+	err = wrap.NewErrorWithAttrs("error", "key1", 1, "key2", 2, "key3", 3)
+	assertEqualAttrSlices(
+		t,
+		wrap.Attrs(err),
+		[]slog.Attr{slog.Int("key1", 1), slog.Int("key2", 2), slog.Int(wrap.DroppedAttrsKey, 1)},
+	)
+}
+
+func TestConfigFromEnvWithInvalidValues(t *testing.T) {
+	t.Setenv("WRAP_RECORD_CALLERS", "yes please")
+	t.Setenv("WRAP_MAX_ATTRS", "many")
+	t.Setenv("WRAP_WIDTH_AWARE_FORMATTING", "true")
+
+	config, err := wrap.ConfigFromEnv()
+
+	expected := `invalid error configuration in environment
+- invalid boolean 'yes please' in WRAP_RECORD_CALLERS
+- invalid integer 'many' in WRAP_MAX_ATTRS`
+
+	assertEqualErrorStrings(t, err, expected)
+	if !config.WidthAwareFormatting || config.RecordCallers || config.MaxAttrs != 0 {
+		t.Errorf("expected valid variables to be applied and invalid ones ignored, got %+v", config)
+	}
+}
@@ -0,0 +1,35 @@
+package ctxwrap
+
+import (
+	"context"
+	"log/slog"
+
+	"hermannm.dev/wrap"
+)
+
+type contextAttrsKey struct{}
+
+// WithAttrs returns a copy of the given context with the given structured log attributes added, on
+// the same format as [wrap.ErrorWithAttrs]. Attributes already added to the parent context are
+// kept. Logging integrations such as [hermannm.dev/wrap/wrapslog] read these attributes from the
+// contexts attached to errors, so that request-scoped attributes (e.g. a user ID) are included when
+// an error is logged.
+func WithAttrs(ctx context.Context, attrs ...any) context.Context {
+	parentAttrs := ContextAttrs(ctx)
+
+	newAttrs := make([]slog.Attr, 0, len(parentAttrs)+len(attrs))
+	newAttrs = append(newAttrs, parentAttrs...)
+	newAttrs = append(newAttrs, wrap.ParseAttrs(attrs)...)
+
+	return context.WithValue(ctx, contextAttrsKey{}, newAttrs)
+}
+
+// ContextAttrs returns the structured log attributes added to the given context with [WithAttrs].
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	attrs, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+	return attrs
+}
@@ -0,0 +1,25 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+func TestWithAttrs(t *testing.T) {
+	parent := ctxwrap.WithAttrs(context.Background(), "user_id", 123)
+	child := ctxwrap.WithAttrs(parent, slog.String("request_id", "abc"))
+
+	assertEqualAttrs(t, ctxwrap.ContextAttrs(parent), []slog.Attr{slog.Int("user_id", 123)})
+	assertEqualAttrs(
+		t,
+		ctxwrap.ContextAttrs(child),
+		[]slog.Attr{slog.Int("user_id", 123), slog.String("request_id", "abc")},
+	)
+
+	if attrs := ctxwrap.ContextAttrs(context.Background()); len(attrs) != 0 {
+		t.Errorf("expected no attributes for context without attributes, got %v", attrs)
+	}
+}
@@ -0,0 +1,115 @@
+package ctxwrap
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+// CancelCauseFunc cancels a context created with [WithCancelCause]. It wraps the given cause with a
+// message and structured log attributes (on the same format as [wrap.ErrorWithAttrs]), and uses the
+// result as the context's cancel cause. If the cause is nil, [context.Canceled] is wrapped instead.
+//
+// Like [context.CancelCauseFunc], only the first call has an effect.
+type CancelCauseFunc func(cause error, message string, attrs ...any)
+
+// WithCancelCause works like [context.WithCancelCause], except that the returned cancel function
+// constructs the cause as a wrapped error with attributes, instead of taking a plain error. Errors
+// created by this package from the context (or its children) record the cause, and include its
+// attributes in their own, so that the reason for the cancellation shows up when the error is
+// logged.
+//
+// Example:
+//
+//	ctx, cancel := ctxwrap.WithCancelCause(ctx)
+//	defer cancel(nil, "request completed")
+//
+//	if err := validateQuota(user); err != nil {
+//		cancel(err, "quota check failed", "user_id", user.ID)
+//	}
+//
+// [context.Cause] returns the wrapped cause after the context is canceled.
+func WithCancelCause(parent context.Context) (ctx context.Context, cancel CancelCauseFunc) {
+	ctx, cancelCause := context.WithCancelCause(parent)
+	return ctx, func(cause error, message string, attrs ...any) {
+		if cause == nil {
+			cause = context.Canceled
+		}
+		cancelCause(wrap.ErrorWithAttrs(cause, message, attrs...))
+	}
+}
+
+// Log attribute keys used to tag errors created from contexts that were already done, if enabled
+// with [SetTagContextDone].
+const (
+	// ContextDoneKey is the key for a boolean attribute that is true if the context was canceled or
+	// its deadline was exceeded when the error was created.
+	ContextDoneKey = "ctx_done"
+	// ContextCauseKey is the key for the message of the context's cause (see [context.Cause]).
+	ContextCauseKey = "ctx_cause"
+	// ContextDeadlineRemainingKey is the key for the time remaining until the context's deadline
+	// when the error was created (negative if the deadline had passed). Only included for contexts
+	// with a deadline.
+	ContextDeadlineRemainingKey = "ctx_deadline_remaining"
+)
+
+var tagContextDone atomic.Bool
+
+// SetTagContextDone sets whether errors created by this package should be tagged with log
+// attributes describing the context's state, when the given context is already done (canceled or
+// past its deadline) at the time the error is created. The attributes use the keys
+// [ContextDoneKey], [ContextCauseKey] and [ContextDeadlineRemainingKey]. This tells you whether a
+// failure was a real error or fallout from a canceled request, which is otherwise hard to see from
+// the error message alone. It is disabled by default.
+func SetTagContextDone(tag bool) {
+	tagContextDone.Store(tag)
+}
+
+// Returns the attributes to tag errors created from the given context with, if tagging is enabled
+// with SetTagContextDone and the context is done.
+func contextDoneAttrs(ctx context.Context) []slog.Attr {
+	if !tagContextDone.Load() || ctx == nil || ctx.Err() == nil {
+		return nil
+	}
+
+	attrs := []slog.Attr{
+		slog.Bool(ContextDoneKey, true),
+		slog.String(ContextCauseKey, context.Cause(ctx).Error()),
+	}
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+		attrs = append(attrs, slog.Duration(ContextDeadlineRemainingKey, time.Until(deadline)))
+	}
+	return attrs
+}
+
+// CheckContext returns nil if the given context is not done, and otherwise wraps the context's
+// cause (see [context.Cause]) with the given message, and attaches the context to it. Use it as a
+// guard in long-running loops:
+//
+//	for _, job := range jobs {
+//		if err := ctxwrap.CheckContext(ctx, "job processing interrupted"); err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//
+// The cause is wrapped directly, so [errors.Is] matches [context.Canceled] or
+// [context.DeadlineExceeded] for contexts without a custom cause, and the attributes of causes from
+// [WithCancelCause] are included once.
+func CheckContext(ctx context.Context, message string) error {
+	if ctx == nil || ctx.Err() == nil {
+		return nil
+	}
+
+	err := &contextError{
+		wrapped: wrapLayer[wrappingError](wrap.Error(context.Cause(ctx), message)),
+		ctx:     contextToStore(ctx),
+		// The cause is not recorded separately, since it is the wrapped error
+		stateAttrs: contextStateAttrs(ctx),
+	}
+	// Skips addVerboseStack and CheckContext
+	return addVerboseStack(ctx, withNetError(err), 2)
+}
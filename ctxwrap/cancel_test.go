@@ -0,0 +1,125 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+func TestWithCancelCause(t *testing.T) {
+	ctx, cancel := ctxwrap.WithCancelCause(context.Background())
+
+	quotaErr := errors.New("quota exceeded")
+	cancel(quotaErr, "quota check failed", "user_id", 123)
+	cancel(errors.New("ignored"), "second cancel has no effect")
+
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, quotaErr) {
+		t.Fatalf("expected context cause to wrap given error, got %v", cause)
+	}
+
+	expectedCause := `quota check failed
+- quota exceeded`
+	assertEqualErrorStrings(t, cause, expectedCause)
+
+	wrapped := ctxwrap.ErrorWithAttrs(ctx, ctx.Err(), "failed to process request", "attempt", 2)
+
+	expected := `failed to process request
+- context canceled`
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	assertEqualAttrs(
+		t,
+		wrap.Attrs(wrapped),
+		[]slog.Attr{slog.Int("attempt", 2), slog.Int("user_id", 123)},
+	)
+}
+
+func TestWithCancelCauseNil(t *testing.T) {
+	ctx, cancel := ctxwrap.WithCancelCause(context.Background())
+	cancel(nil, "request completed")
+
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, context.Canceled) {
+		t.Errorf("expected context cause to wrap context.Canceled, got %v", cause)
+	}
+}
+
+func TestErrorWithoutCancelCause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	wrapped := ctxwrap.Error(ctx, ctx.Err(), "failed to process request")
+	if attrs := wrap.Attrs(wrapped); len(attrs) != 0 {
+		t.Errorf("expected no attributes for context without custom cause, got %v", attrs)
+	}
+}
+
+func TestSetTagContextDone(t *testing.T) {
+	ctxwrap.SetTagContextDone(true)
+	defer ctxwrap.SetTagContextDone(false)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	wrapped := ctxwrap.Error(ctx, errors.New("error"), "wrapped error")
+	if attrs := wrap.Attrs(wrapped); len(attrs) != 0 {
+		t.Errorf("expected no attributes for context that is not done, got %v", attrs)
+	}
+
+	cause := errors.New("client disconnected")
+	cancel(cause)
+	wrapped = ctxwrap.Error(ctx, errors.New("error"), "wrapped error")
+	assertEqualAttrs(t, wrap.Attrs(wrapped), []slog.Attr{
+		slog.Bool(ctxwrap.ContextDoneKey, true),
+		slog.String(ctxwrap.ContextCauseKey, "client disconnected"),
+	})
+
+	deadlineCtx, cancelDeadline := context.WithDeadline(
+		context.Background(),
+		time.Now().Add(-time.Second),
+	)
+	defer cancelDeadline()
+	wrapped = ctxwrap.Errors(deadlineCtx, "wrapped errors", errors.New("error"))
+
+	attrs := wrap.Attrs(wrapped)
+	if len(attrs) != 3 || attrs[2].Key != ctxwrap.ContextDeadlineRemainingKey {
+		t.Fatalf("expected remaining deadline attribute, got %v", attrs)
+	}
+	if remaining := attrs[2].Value.Duration(); remaining >= 0 {
+		t.Errorf("expected negative remaining deadline, got %v", remaining)
+	}
+	if cause := attrs[1].Value.String(); cause != context.DeadlineExceeded.Error() {
+		t.Errorf("unexpected context cause %q", cause)
+	}
+}
+
+func TestCheckContext(t *testing.T) {
+	ctx, cancel := ctxwrap.WithCancelCause(context.Background())
+	if err := ctxwrap.CheckContext(ctx, "processing interrupted"); err != nil {
+		t.Fatalf("expected nil error for context that is not done, got %v", err)
+	}
+
+	cancel(errors.New("shutting down"), "server stopped", "signal", "SIGTERM")
+	err := ctxwrap.CheckContext(ctx, "processing interrupted")
+
+	expected := `processing interrupted
+- server stopped
+- shutting down`
+	assertEqualErrorStrings(t, err, expected)
+	assertEqualAttrs(t, wrap.Attrs(err), []slog.Attr{slog.String("signal", "SIGTERM")})
+
+	if stored, _ := ctxwrap.ContextFrom(err); stored != ctx {
+		t.Error("expected error to carry the context")
+	}
+
+	deadlineCtx, cancelDeadline := context.WithTimeout(context.Background(), 0)
+	defer cancelDeadline()
+	err = ctxwrap.CheckContext(deadlineCtx, "timed out")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+}
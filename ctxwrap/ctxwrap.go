@@ -0,0 +1,317 @@
+// Package ctxwrap provides the same error wrapping functions as [hermannm.dev/wrap], but with an
+// additional [context.Context] parameter. The context is attached to the returned error, so that
+// logging libraries can extract context values (such as request-scoped log attributes or trace IDs)
+// when the error is logged, even after the context has gone out of scope. To store only the values
+// that are logged instead of the whole context, see [SetSnapshotContexts].
+//
+// A nil context is treated as [context.Background]. To return errors created from either of them
+// without a context attached, see [SetSkipEmptyContexts].
+//
+// The context is available through the error's Context method (see
+// [hermannm.dev/wrap/errschema.ErrorWithContext]), which is used by e.g. [hermannm.dev/devlog/log].
+package ctxwrap
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/errschema"
+	"hermannm.dev/wrap/internal/neterr"
+)
+
+// Error wraps the given error with a message for context, and attaches the given context to it.
+// The error is displayed in the same format as [wrap.Error].
+//
+// If the context was canceled with a cause (see [context.WithCancelCause] and [WithCancelCause]),
+// the error records the cause, and includes the cause's log attributes in its own. If the context
+// is flagged with [wrap.WithVerboseErrors], the error also captures a stack trace (see
+// [wrap.Stack]). To tag errors created from contexts that are already done, see
+// [SetTagContextDone].
+func Error(ctx context.Context, wrapped error, message string) error {
+	return newContextError(ctx, wrap.Error(wrapped, message))
+}
+
+// Errorf wraps the given error with a message for context, and attaches the given context to it. It
+// forwards the given message format and args to [fmt.Sprintf] to construct the message. The error
+// is displayed in the same format as [wrap.Errorf].
+func Errorf(ctx context.Context, wrapped error, messageFormat string, formatArgs ...any) error {
+	return newContextError(ctx, wrap.Errorf(wrapped, messageFormat, formatArgs...))
+}
+
+// ErrorWithAttrs wraps the given error with a message for context, and attaches the given context
+// and structured log attributes to it. The error is displayed in the same format as
+// [wrap.ErrorWithAttrs].
+func ErrorWithAttrs(ctx context.Context, wrapped error, message string, attrs ...any) error {
+	return newContextError(ctx, wrap.ErrorWithAttrs(wrapped, message, attrs...))
+}
+
+// Errors wraps the given errors with a message for context, and attaches the given context to it.
+// The error is displayed in the same format as [wrap.Errors].
+func Errors(ctx context.Context, message string, wrapped ...error) error {
+	wrappedErrs := wrap.Errors(message, wrapped...)
+	if skipContext(ctx) {
+		return wrappedErrs
+	}
+
+	err := &contextErrors{
+		wrapped:    wrapLayer[wrappingErrors](wrappedErrs),
+		ctx:        contextToStore(orBackground(ctx)),
+		cause:      contextCause(ctx),
+		stateAttrs: contextStateAttrs(ctx),
+	}
+	// Skips addVerboseStack and Errors
+	return addVerboseStack(ctx, withNetError(err), 2)
+}
+
+type (
+	wrappingError  = errschema.WrappedError
+	wrappingErrors = errschema.WrappedErrors
+)
+
+func newContextError(ctx context.Context, wrapped error) error {
+	if skipContext(ctx) {
+		return wrapped
+	}
+
+	err := &contextError{
+		wrapped:    wrapLayer[wrappingError](wrapped),
+		ctx:        contextToStore(orBackground(ctx)),
+		cause:      contextCause(ctx),
+		stateAttrs: contextStateAttrs(ctx),
+	}
+	// Skips addVerboseStack, newContextError and the exported function calling it
+	return addVerboseStack(ctx, withNetError(err), 3)
+}
+
+// Returns the layer created by a wrap function, looking through the marker that the wrap package
+// adds around layers whose chain contains a net.Error. The context error replaces that marker with
+// its own net.Error variant (see withNetError).
+func wrapLayer[Layer error](err error) Layer {
+	for {
+		if layer, ok := err.(Layer); ok {
+			return layer
+		}
+		err = errors.Unwrap(err)
+	}
+}
+
+// Returns the net.Error variant of the given context error if it wraps a net.Error (see
+// neterr.Wraps), so that it still satisfies net.Error for code that type-asserts to it instead of
+// using errors.As. Other errors are returned unchanged, so that they don't pass such checks.
+func withNetError(err error) error {
+	if !neterr.Wraps(err) {
+		return err
+	}
+
+	switch err := err.(type) {
+	case *contextError:
+		return netContextError{err}
+	case *contextErrors:
+		return netContextErrors{err}
+	default:
+		return err
+	}
+}
+
+// Attaches a stack trace to the given error if the context is flagged with wrap.WithVerboseErrors,
+// skipping the given number of frames (see wrap.CaptureStack).
+func addVerboseStack(ctx context.Context, err error, skip int) error {
+	if !wrap.VerboseErrors(ctx) {
+		return err
+	}
+	return wrap.New(err).With(wrap.WithStackTrace(wrap.CaptureStack(skip))).Err()
+}
+
+type contextError struct {
+	wrapped wrappingError
+	ctx     context.Context
+	cause   error
+	// Attributes describing the state of the context when the error was created (see
+	// contextStateAttrs).
+	stateAttrs []slog.Attr
+}
+
+func (err *contextError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *contextError) Unwrap() error {
+	return err.wrapped.Unwrap()
+}
+
+// WrappingMessage implements [errschema.WrappedError] for log message formatting.
+func (err *contextError) WrappingMessage() string {
+	return err.wrapped.WrappingMessage()
+}
+
+// Is forwards to the wrapped layer (for errors embedded with %w in [Errorf]), for [errors.Is].
+// Unwrap skips the wrapped layer, so errors.Is would not check it otherwise.
+func (err *contextError) Is(target error) bool {
+	is, ok := err.wrapped.(interface{ Is(error) bool })
+	return ok && is.Is(target)
+}
+
+// As forwards to the wrapped layer (for errors embedded with %w in [Errorf]), for [errors.As].
+func (err *contextError) As(target any) bool {
+	as, ok := err.wrapped.(interface{ As(any) bool })
+	return ok && as.As(target)
+}
+
+// LogAttrs returns the log attributes attached to this error, the request ID of the context (see
+// [WithRequestID]), and the attributes of the context's cancel cause if there is one.
+func (err *contextError) LogAttrs() []slog.Attr {
+	return logAttrs(err.ctx, err.wrapped, err.cause, err.stateAttrs)
+}
+
+// Context returns the context attached to the error, for logging libraries that look for this
+// method (such as [hermannm.dev/devlog/log]).
+func (err *contextError) Context() context.Context {
+	return err.ctx
+}
+
+type contextErrors struct {
+	wrapped    wrappingErrors
+	ctx        context.Context
+	cause      error
+	stateAttrs []slog.Attr
+}
+
+func (err *contextErrors) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *contextErrors) Unwrap() []error {
+	return err.wrapped.Unwrap()
+}
+
+// WrappingMessage implements [errschema.WrappedError] for log message formatting.
+func (err *contextErrors) WrappingMessage() string {
+	return err.wrapped.WrappingMessage()
+}
+
+// Is forwards to the wrapped layer (which indexes the errors of large multi-errors), for
+// [errors.Is].
+func (err *contextErrors) Is(target error) bool {
+	is, ok := err.wrapped.(interface{ Is(error) bool })
+	return ok && is.Is(target)
+}
+
+// LogAttrs returns the request ID of the context (see [WithRequestID]), and the attributes of the
+// context's cancel cause if there is one.
+func (err *contextErrors) LogAttrs() []slog.Attr {
+	return logAttrs(err.ctx, err.wrapped, err.cause, err.stateAttrs)
+}
+
+// Context returns the context attached to the error, for logging libraries that look for this
+// method (such as [hermannm.dev/devlog/log]).
+func (err *contextErrors) Context() context.Context {
+	return err.ctx
+}
+
+// A contextError whose chain contains a [net.Error] (see withNetError).
+type netContextError struct {
+	*contextError
+}
+
+// Timeout forwards to the first [net.Error] in the chain.
+func (err netContextError) Timeout() bool {
+	return neterr.Timeout(err.contextError)
+}
+
+// Temporary forwards to the first [net.Error] in the chain.
+func (err netContextError) Temporary() bool {
+	return neterr.Temporary(err.contextError)
+}
+
+// A contextErrors whose chain contains a [net.Error] (see withNetError).
+type netContextErrors struct {
+	*contextErrors
+}
+
+// Timeout forwards to the first [net.Error] in the chain.
+func (err netContextErrors) Timeout() bool {
+	return neterr.Timeout(err.contextErrors)
+}
+
+// Temporary forwards to the first [net.Error] in the chain.
+func (err netContextErrors) Temporary() bool {
+	return neterr.Temporary(err.contextErrors)
+}
+
+// Returns the attributes of the given wrap layer (not including the errors it wraps), followed by
+// the request ID of the given context, the given context state attributes (see contextStateAttrs)
+// and the attributes of the given cancel cause (including the errors it wraps). Nested errors from
+// the same request each include the request ID, but wrap.Attrs includes it once.
+func logAttrs(ctx context.Context, layer error, cause error, stateAttrs []slog.Attr) []slog.Attr {
+	var attrs []slog.Attr
+	if withAttrs, ok := layer.(errschema.ErrorWithAttrs); ok {
+		attrs = append(attrs, withAttrs.LogAttrs()...)
+	}
+	if requestID, ok := RequestID(ctx); ok {
+		attrs = append(attrs, slog.String(wrap.RequestIDKey, requestID))
+	}
+	attrs = append(attrs, stateAttrs...)
+	if cause != nil {
+		attrs = append(attrs, wrap.Attrs(cause)...)
+	}
+	return attrs
+}
+
+var skipEmptyContexts atomic.Bool
+
+// SetSkipEmptyContexts sets whether errors created by this package from a nil context or
+// [context.Background] should be returned without a context attached. These contexts carry no
+// values, deadline or cause, so skipping them saves a layer in the error chain. Contexts are still
+// attached if missing contexts are tagged (see [SetTagMissingContext]), to tag the error. It is
+// disabled by default.
+func SetSkipEmptyContexts(skip bool) {
+	skipEmptyContexts.Store(skip)
+}
+
+// Returns true if errors created from the given context should be returned without a context (see
+// SetSkipEmptyContexts).
+func skipContext(ctx context.Context) bool {
+	return skipEmptyContexts.Load() &&
+		(ctx == nil || ctx == context.Background()) &&
+		!tagMissingContext.Load()
+}
+
+// Returns the given context, or context.Background if it is nil, so that errors never store a nil
+// context.
+func orBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// Returns the attributes describing the state of the given context when an error is created from
+// it: the missing context tag (see SetTagMissingContext) and the done context tags (see
+// SetTagContextDone). They are computed when the error is created, since a nil context is replaced
+// by context.Background, and the done state changes over time.
+func contextStateAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if missingAttr, ok := missingContextAttr(ctx); ok {
+		attrs = append(attrs, missingAttr)
+	}
+	return append(attrs, contextDoneAttrs(ctx)...)
+}
+
+// Returns the cause that the given context was canceled with, if it was canceled with a custom
+// cause (i.e. one other than the standard context.Canceled and context.DeadlineExceeded).
+func contextCause(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+
+	cause := context.Cause(ctx)
+	if cause == nil || cause == ctx.Err() {
+		return nil
+	}
+	return cause
+}
@@ -0,0 +1,303 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+type contextKey struct{}
+
+func TestError(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+
+	err := errors.New("error")
+	inner := ctxwrap.Error(ctx, err, "inner wrapped error")
+	outer := wrap.Error(inner, "outer wrapped error")
+
+	expected := `outer wrapped error
+- inner wrapped error
+- error`
+
+	assertEqualErrorStrings(t, outer, expected)
+	assertContextValue(t, inner, "value")
+
+	if !errors.Is(outer, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	err := errors.New("username already taken")
+	wrapped := ctxwrap.Errorf(
+		context.Background(),
+		err,
+		"failed to create user with name '%s'",
+		"hermannm",
+	)
+
+	expected := `failed to create user with name 'hermannm'
+- username already taken`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
+
+func TestErrorfWithWrappedArgs(t *testing.T) {
+	err := errors.New("connection refused")
+	embedded := errors.New("primary unavailable")
+	wrapped := ctxwrap.Errorf(context.Background(), err, "failover failed: %w", embedded)
+
+	if !errors.Is(wrapped, err) || !errors.Is(wrapped, embedded) {
+		t.Error("expected errors.Is to match both wrapped and embedded errors")
+	}
+}
+
+func TestErrorWithAttrs(t *testing.T) {
+	err := errors.New("username already taken")
+	wrapped := ctxwrap.ErrorWithAttrs(
+		context.Background(),
+		err,
+		"failed to create user",
+		"username",
+		"hermannm",
+	)
+
+	expected := `failed to create user
+- username already taken`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrs(t, wrap.Attrs(wrapped), []slog.Attr{slog.String("username", "hermannm")})
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	wrapped := ctxwrap.Errors(ctx, "wrapped errors", err1, err2)
+	outer := wrap.Error(wrapped, "outer wrapped error")
+
+	expected := `outer wrapped error
+- wrapped errors
+  - error 1
+  - error 2`
+
+	assertEqualErrorStrings(t, outer, expected)
+	assertContextValue(t, wrapped, "value")
+
+	if !errors.Is(outer, err2) {
+		t.Error("expected errors.Is to return true for wrapped errors")
+	}
+}
+
+func TestFlattenIncludesContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	err := wrap.Error(ctxwrap.Error(ctx, errors.New("error"), "inner wrapped error"), "outer")
+
+	layers := wrap.Flatten(err)
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 layers, got %d", len(layers))
+	}
+	if layers[0].Context != nil {
+		t.Error("expected outer layer to have no context")
+	}
+	if layers[1].Context != ctx {
+		t.Error("expected inner layer to have the attached context")
+	}
+}
+
+func assertContextValue(t *testing.T, err error, expected string) {
+	t.Helper()
+
+	withContext, ok := err.(interface{ Context() context.Context })
+	if !ok {
+		t.Fatalf("expected error to implement Context method, got %T", err)
+	}
+
+	if value := withContext.Context().Value(contextKey{}); value != expected {
+		t.Errorf("unexpected context value; got %v, want %s", value, expected)
+	}
+}
+
+func assertEqualAttrs(t *testing.T, actual []slog.Attr, expected []slog.Attr) {
+	t.Helper()
+
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected attributes; got %v, want %v", actual, expected)
+	}
+	for i := range actual {
+		if !actual[i].Equal(expected[i]) {
+			t.Errorf("unexpected attribute at index %d; got %v, want %v", i, actual[i], expected[i])
+		}
+	}
+}
+
+func assertEqualErrorStrings(t *testing.T, errToTest error, expected string) {
+	t.Helper()
+
+	if actual := errToTest.Error(); actual != expected {
+		t.Errorf(`unexpected error string
+got:
+----------------------------------------
+%s
+----------------------------------------
+
+want:
+----------------------------------------
+%s
+----------------------------------------
+`, actual, expected)
+	}
+}
+
+func TestWrappedNetError(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	err := &net.OpError{Op: "dial", Net: "tcp", Err: context.DeadlineExceeded}
+
+	for _, wrapped := range []error{
+		ctxwrap.Error(ctx, err, "failed to connect"),
+		ctxwrap.Errorf(ctx, err, "failed to connect to %s", "db"),
+		ctxwrap.Errors(ctx, "failed to connect", errors.New("error"), err),
+	} {
+		if netErr, ok := wrapped.(net.Error); !ok || !netErr.Timeout() {
+			t.Errorf("expected error to implement net.Error with forwarded Timeout: %v", wrapped)
+		}
+		assertContextValue(t, wrapped, "value")
+	}
+
+	for _, wrapped := range []error{
+		ctxwrap.Error(ctx, errors.New("error"), "wrapped error"),
+		ctxwrap.Errors(ctx, "wrapped errors", errors.New("error 1"), errors.New("error 2")),
+	} {
+		if _, ok := wrapped.(net.Error); ok {
+			t.Errorf("expected error without net.Error in chain to not implement it: %v", wrapped)
+		}
+	}
+}
+
+// Pins the documented difference between ctxwrap errors and contexts attached with
+// wrap.WithContext, which only attach the context itself.
+func TestWrapWithContext(t *testing.T) {
+	ctx, cancel := ctxwrap.WithCancelCause(ctxwrap.WithRequestID(context.Background(), "request-1"))
+	cancel(errors.New("quota exceeded"), "quota check failed", "user_id", 123)
+
+	ctxwrapErr := ctxwrap.Error(ctx, errors.New("error"), "wrapped error")
+	assertEqualAttrs(
+		t,
+		wrap.Attrs(ctxwrapErr),
+		[]slog.Attr{slog.String("request_id", "request-1"), slog.Int("user_id", 123)},
+	)
+
+	wrapErr := wrap.E(errors.New("error"), "wrapped error", wrap.WithContext(ctx))
+	assertEqualAttrs(t, wrap.Attrs(wrapErr), nil)
+	if _, ok := wrap.RequestID(wrapErr); ok {
+		t.Error("expected error from wrap.WithContext to not include request ID")
+	}
+
+	var withContext interface{ Context() context.Context }
+	if !errors.As(wrapErr, &withContext) || withContext.Context() != ctx {
+		t.Error("expected error from wrap.WithContext to have the context")
+	}
+}
+
+func TestErrorIdentity(t *testing.T) {
+	ctx := ctxwrap.WithRequestID(context.Background(), "request-1")
+	err := errors.New("error")
+	constructors := map[string]func() error{
+		"Error":  func() error { return ctxwrap.Error(ctx, err, "wrapped error") },
+		"Errors": func() error { return ctxwrap.Errors(ctx, "wrapped errors", err) },
+		"CheckContext": func() error {
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+			return ctxwrap.CheckContext(canceled, "interrupted")
+		},
+	}
+
+	for name, constructor := range constructors {
+		// Comparing errors that store log attributes must not panic
+		wrapped1, wrapped2 := constructor(), constructor()
+		if wrapped1 == wrapped2 || errors.Is(wrapped1, wrapped2) {
+			t.Errorf("%s: expected separately created errors to be distinct", name)
+		}
+		if !errors.Is(wrapped1, wrapped1) {
+			t.Errorf("%s: expected errors.Is to match the same error instance", name)
+		}
+	}
+}
+
+func TestVerboseErrors(t *testing.T) {
+	ctx := wrap.WithVerboseErrors(context.WithValue(context.Background(), contextKey{}, "value"))
+
+	for _, err := range []error{
+		ctxwrap.Error(ctx, errors.New("error"), "wrapped error"),
+		ctxwrap.Errors(ctx, "wrapped errors", errors.New("error 1"), errors.New("error 2")),
+	} {
+		stack, ok := wrap.Stack(err)
+		if !ok {
+			t.Fatalf("expected stack trace for error with verbose context: %v", err)
+		}
+		frames := stack.Frames()
+		if !strings.HasSuffix(frames[0].Function, "TestVerboseErrors") {
+			t.Errorf("expected stack trace to start at the caller, got %s", frames[0].Function)
+		}
+
+		var withContext interface{ Context() context.Context }
+		if !errors.As(err, &withContext) || withContext.Context().Value(contextKey{}) != "value" {
+			t.Error("expected error with verbose context to have the context")
+		}
+	}
+
+	unflagged := ctxwrap.Error(context.Background(), errors.New("error"), "wrapped error")
+	if _, ok := wrap.Stack(unflagged); ok {
+		t.Error("expected no stack trace for error with unflagged context")
+	}
+}
+
+func TestNilAndBackgroundContext(t *testing.T) {
+	for _, ctx := range []context.Context{nil, context.Background()} {
+		for _, wrapped := range []error{
+			ctxwrap.Error(ctx, errors.New("error"), "wrapped error"),
+			ctxwrap.Errors(ctx, "wrapped errors", errors.New("error")),
+		} {
+			if ctx, _ := ctxwrap.ContextFrom(wrapped); ctx != context.Background() {
+				t.Errorf("expected context to be attached as Background, got %v", ctx)
+			}
+		}
+	}
+
+	ctxwrap.SetSkipEmptyContexts(true)
+	defer ctxwrap.SetSkipEmptyContexts(false)
+
+	for _, ctx := range []context.Context{nil, context.Background()} {
+		err := errors.New("error")
+		for _, wrapped := range []error{
+			ctxwrap.Error(ctx, err, "wrapped error"),
+			ctxwrap.Errors(ctx, "wrapped errors", err),
+		} {
+			if _, hasContext := ctxwrap.ContextFrom(wrapped); hasContext {
+				t.Errorf("expected no context to be attached for %v context", ctx)
+			}
+			if !errors.Is(wrapped, err) {
+				t.Error("expected errors.Is to return true for wrapped error")
+			}
+		}
+	}
+
+	// Contexts are still attached when tagging missing contexts, with nil replaced by Background
+	ctxwrap.SetTagMissingContext(true)
+	defer ctxwrap.SetTagMissingContext(false)
+
+	wrapped := ctxwrap.Error(nil, errors.New("error"), "wrapped error")
+	if ctx, _ := ctxwrap.ContextFrom(wrapped); ctx != context.Background() {
+		t.Errorf("expected nil context to be replaced by Background, got %v", ctx)
+	}
+	if ctxwrap.CheckContext(nil, "interrupted") != nil {
+		t.Error("expected CheckContext to return nil for nil context")
+	}
+}
@@ -0,0 +1,43 @@
+package ctxwrap
+
+import (
+	"context"
+
+	"hermannm.dev/wrap"
+)
+
+// Defer wraps the error pointed to by errPtr with a message for context, and attaches the given
+// context to it, if the error is non-nil. It works like [wrap.Defer], for functions that take a
+// context:
+//
+//	func (handler *OrderHandler) ProcessOrder(ctx context.Context, order Order) (err error) {
+//		defer ctxwrap.Defer(ctx, &err, "failed to process order")
+//
+//		if err := handler.validateOrder(ctx, order); err != nil {
+//			return err
+//		}
+//		return handler.saveOrder(ctx, order)
+//	}
+//
+// If the error is nil, it is left unchanged.
+func Defer(ctx context.Context, errPtr *error, message string) {
+	if *errPtr != nil {
+		*errPtr = newContextError(ctx, wrap.Error(*errPtr, message))
+	}
+}
+
+// Deferf wraps the error pointed to by errPtr with a formatted message for context, and attaches
+// the given context to it, if the error is non-nil. It works like [Defer], but forwards the given
+// message format and args to [fmt.Sprintf] to construct the message (like [Errorf]).
+//
+// Example:
+//
+//	func (handler *OrderHandler) ProcessOrder(ctx context.Context, orderID int) (err error) {
+//		defer ctxwrap.Deferf(ctx, &err, "failed to process order %d", orderID)
+//		// ...
+//	}
+func Deferf(ctx context.Context, errPtr *error, messageFormat string, formatArgs ...any) {
+	if *errPtr != nil {
+		*errPtr = newContextError(ctx, wrap.Errorf(*errPtr, messageFormat, formatArgs...))
+	}
+}
@@ -0,0 +1,57 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+func TestDefer(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+
+	processOrder := func(ctx context.Context, fail bool) (err error) {
+		defer ctxwrap.Defer(ctx, &err, "failed to process order")
+
+		if fail {
+			return errors.New("order not found")
+		}
+		return nil
+	}
+
+	err := processOrder(ctx, true)
+
+	expected := `failed to process order
+- order not found`
+	assertEqualErrorStrings(t, err, expected)
+	assertContextValue(t, err, "value")
+
+	if err := processOrder(ctx, false); err != nil {
+		t.Errorf("expected nil error to be left unchanged, got %v", err)
+	}
+}
+
+func TestDeferf(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+
+	processOrder := func(ctx context.Context, orderID int, fail bool) (err error) {
+		defer ctxwrap.Deferf(ctx, &err, "failed to process order %d", orderID)
+
+		if fail {
+			return errors.New("order not found")
+		}
+		return nil
+	}
+
+	err := processOrder(ctx, 123, true)
+
+	expected := `failed to process order 123
+- order not found`
+	assertEqualErrorStrings(t, err, expected)
+	assertContextValue(t, err, "value")
+
+	if err := processOrder(ctx, 123, false); err != nil {
+		t.Errorf("expected nil error to be left unchanged, got %v", err)
+	}
+}
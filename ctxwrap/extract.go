@@ -0,0 +1,98 @@
+package ctxwrap
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ContextExtractor extracts structured log attributes from a context, typically by reading context
+// values set by another library (such as a trace ID). Register extractors with [RegisterExtractor].
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+// The registered extractors. The list is copied on write, so that readers can use it without
+// holding a lock while calling extractors.
+var (
+	extractors     atomic.Pointer[[]ContextExtractor]
+	extractorsLock sync.Mutex // Serializes writers
+)
+
+// RegisterExtractor adds an extractor to the list used by [ExtractAttrs]. Extractors run in the
+// order they were registered. It is safe to call concurrently, but is typically called at program
+// startup.
+//
+// Extractors are never called while this package holds a lock, so they may safely call back into
+// this package: extractors may create wrapped errors, call [ExtractAttrs], or register other
+// extractors (which take effect from the next call to ExtractAttrs). Extractors must be safe for
+// concurrent use.
+func RegisterExtractor(extractor ContextExtractor) {
+	extractorsLock.Lock()
+	defer extractorsLock.Unlock()
+
+	var newExtractors []ContextExtractor
+	if oldExtractors := extractors.Load(); oldExtractors != nil {
+		newExtractors = slices.Clone(*oldExtractors)
+	}
+	newExtractors = append(newExtractors, extractor)
+	extractors.Store(&newExtractors)
+}
+
+// SlogExtractor adapts an extractor with the signature used by slog middleware packages to a
+// [ContextExtractor], so that context attributes stored by those packages are included in errors.
+// For example, the ExtractPrepended and ExtractAppended functions of
+// github.com/veqryn/slog-context have this signature:
+//
+//	ctxwrap.RegisterExtractor(ctxwrap.SlogExtractor(slogctx.ExtractPrepended))
+//
+// The record time, level and message are not available when extracting attributes for an error, so
+// the extractor is called with the current time, [slog.LevelError] and an empty message. Readers
+// with the signature func(ctx context.Context) []slog.Attr (such as the ones for devlog's context
+// attributes) don't need adapting, and can be passed to RegisterExtractor directly.
+func SlogExtractor(
+	extractor func(
+		ctx context.Context,
+		recordTime time.Time,
+		recordLevel slog.Level,
+		recordMessage string,
+	) []slog.Attr,
+) ContextExtractor {
+	return func(ctx context.Context) []slog.Attr {
+		return extractor(ctx, time.Now(), slog.LevelError, "")
+	}
+}
+
+// ExtractAttrs converts the context attached to the given error into structured log attributes.
+// This is the step where contexts leave an error: serializers, reporters and logging integrations
+// should call it whenever they encode an error, since contexts themselves can't be serialized. Only
+// the values covered by ExtractAttrs survive; all other context values are dropped.
+//
+// The attributes are the ones added with [WithAttrs], followed by the ones returned by registered
+// extractors (see [RegisterExtractor]). They are read from all the contexts attached to the error
+// chain (see [MergedContext]), preferring the innermost context, since contexts are typically
+// passed down the call stack, so the innermost context is the most specific one. Multi-errors are
+// not traversed, since the contexts of one branch don't apply to the error as a whole.
+//
+// If no context is attached to the error, ExtractAttrs returns nil.
+func ExtractAttrs(err error) []slog.Attr {
+	if _, ok := ContextFrom(err); !ok {
+		return nil
+	}
+	return extractAttrs(MergedContext(err))
+}
+
+// Returns the attributes added to the given context with WithAttrs, followed by the ones returned
+// by registered extractors.
+func extractAttrs(ctx context.Context) []slog.Attr {
+	attrs := slices.Clone(ContextAttrs(ctx))
+
+	if registered := extractors.Load(); registered != nil {
+		for _, extractor := range *registered {
+			attrs = append(attrs, extractor(ctx)...)
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,147 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+type traceIDKey struct{}
+
+func init() {
+	ctxwrap.RegisterExtractor(func(ctx context.Context) []slog.Attr {
+		if traceID, ok := ctx.Value(traceIDKey{}).(string); ok {
+			return []slog.Attr{slog.String("trace_id", traceID)}
+		}
+		return nil
+	})
+}
+
+func TestExtractAttrs(t *testing.T) {
+	outerCtx := ctxwrap.WithAttrs(context.Background(), "user_id", 123)
+	innerCtx := context.WithValue(outerCtx, traceIDKey{}, "trace-1")
+	innerCtx = context.WithValue(innerCtx, contextKey{}, "not extracted")
+
+	inner := ctxwrap.Error(innerCtx, errors.New("error"), "inner wrapped error")
+	outer := ctxwrap.Error(outerCtx, inner, "outer wrapped error")
+
+	assertEqualAttrs(
+		t,
+		ctxwrap.ExtractAttrs(outer),
+		[]slog.Attr{slog.Int("user_id", 123), slog.String("trace_id", "trace-1")},
+	)
+}
+
+func TestExtractAttrsWithoutContext(t *testing.T) {
+	err := wrap.Error(errors.New("error"), "wrapped error")
+	if attrs := ctxwrap.ExtractAttrs(err); attrs != nil {
+		t.Errorf("expected no attributes for error without context, got %v", attrs)
+	}
+}
+
+type reentrantKey struct{}
+
+// Registered once, so that the test can run multiple times with -count.
+var registerReentrantExtractor sync.Once
+
+func TestExtractAttrsReentrant(t *testing.T) {
+	registerReentrantExtractor.Do(func() {
+		ctxwrap.RegisterExtractor(func(ctx context.Context) []slog.Attr {
+			if ctx.Value(reentrantKey{}) == nil {
+				return nil
+			}
+
+			// Extractors may create errors, extract attributes and register extractors themselves
+			nestedCtx := ctxwrap.WithAttrs(context.Background(), "nested", true)
+			nestedErr := ctxwrap.Error(nestedCtx, errors.New("nested error"), "nested error")
+			ctxwrap.RegisterExtractor(func(context.Context) []slog.Attr { return nil })
+			return ctxwrap.ExtractAttrs(nestedErr)
+		})
+	})
+
+	ctx := context.WithValue(context.Background(), reentrantKey{}, true)
+	err := ctxwrap.Error(ctx, errors.New("error"), "wrapped error")
+
+	done := make(chan []slog.Attr)
+	go func() {
+		done <- ctxwrap.ExtractAttrs(err)
+	}()
+
+	select {
+	case attrs := <-done:
+		assertEqualAttrs(t, attrs, []slog.Attr{slog.Bool("nested", true)})
+	case <-time.After(10 * time.Second):
+		t.Fatal("ExtractAttrs deadlocked when called from extractor")
+	}
+}
+
+func TestExtractAttrsConcurrent(t *testing.T) {
+	const goroutines = 16
+	const iterations = 100
+
+	ctx := ctxwrap.WithAttrs(context.Background(), "user_id", 123)
+	ctx = context.WithValue(ctx, traceIDKey{}, "trace-1")
+	err := ctxwrap.Error(ctx, errors.New("error"), "wrapped error")
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < iterations; j++ {
+				if j%50 == 0 {
+					ctxwrap.RegisterExtractor(func(context.Context) []slog.Attr { return nil })
+				}
+
+				attrs := ctxwrap.ExtractAttrs(err)
+				if len(attrs) < 2 || !attrs[0].Equal(slog.Int("user_id", 123)) {
+					t.Errorf("unexpected attributes from concurrent extraction: %v", attrs)
+					return
+				}
+			}
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("concurrent ExtractAttrs calls deadlocked")
+	}
+}
+
+type slogAttrsKey struct{}
+
+func TestSlogExtractor(t *testing.T) {
+	var level slog.Level
+	extractor := ctxwrap.SlogExtractor(
+		func(ctx context.Context, _ time.Time, recordLevel slog.Level, _ string) []slog.Attr {
+			level = recordLevel
+			attrs, _ := ctx.Value(slogAttrsKey{}).([]slog.Attr)
+			return attrs
+		},
+	)
+
+	ctx := context.WithValue(
+		context.Background(),
+		slogAttrsKey{},
+		[]slog.Attr{slog.String("tenant", "acme")},
+	)
+
+	assertEqualAttrs(t, extractor(ctx), []slog.Attr{slog.String("tenant", "acme")})
+	if level != slog.LevelError {
+		t.Errorf("expected extractor to be called with error level, got %v", level)
+	}
+}
@@ -0,0 +1,53 @@
+package ctxwrap
+
+import (
+	"context"
+
+	"hermannm.dev/wrap/errschema"
+)
+
+// ContextFrom returns the innermost context attached to the given error or the errors it wraps
+// (by this package, or by other error types with a Context method, see
+// [errschema.ErrorWithContext]). The innermost context is typically the most specific one, since
+// contexts are passed down the call stack, so it includes the values of the outer contexts. Use
+// [OutermostContextFrom] to get the outermost context instead.
+//
+// Multi-errors are not traversed, since the contexts of one branch don't apply to the error as a
+// whole. It returns false if no context is attached to the error.
+func ContextFrom(err error) (ctx context.Context, ok bool) {
+	forEachContext(err, func(errCtx context.Context) bool {
+		ctx = errCtx
+		return true
+	})
+	return ctx, ctx != nil
+}
+
+// OutermostContextFrom returns the outermost context attached to the given error or the errors it
+// wraps, like [ContextFrom] but from the other end of the chain. Use this when inner layers may be
+// created with unrelated contexts (such as the context of a background worker), and the context of
+// the code that handled the error last (such as a request handler) is the relevant one.
+func OutermostContextFrom(err error) (ctx context.Context, ok bool) {
+	forEachContext(err, func(errCtx context.Context) bool {
+		ctx = errCtx
+		return false
+	})
+	return ctx, ctx != nil
+}
+
+// Calls the given function with each non-nil context attached to the given error chain, outermost
+// first, until it returns false.
+func forEachContext(err error, fn func(ctx context.Context) bool) {
+	for err != nil {
+		if withContext, ok := err.(errschema.ErrorWithContext); ok {
+			if ctx := withContext.Context(); ctx != nil && !fn(ctx) {
+				return
+			}
+		}
+
+		unwrappable, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return
+		}
+		err = unwrappable.Unwrap()
+	}
+}
@@ -0,0 +1,34 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+func TestContextFrom(t *testing.T) {
+	outerCtx := context.WithValue(context.Background(), contextKey{}, "outer")
+	innerCtx := context.WithValue(context.Background(), contextKey{}, "inner")
+
+	inner := ctxwrap.Error(innerCtx, errors.New("error"), "inner wrapped error")
+	middle := wrap.Error(inner, "middle wrapped error")
+	outer := ctxwrap.Error(outerCtx, middle, "outer wrapped error")
+
+	if ctx, ok := ctxwrap.ContextFrom(outer); !ok || ctx.Value(contextKey{}) != "inner" {
+		t.Errorf("expected innermost context, got %v", ctx)
+	}
+	if ctx, ok := ctxwrap.OutermostContextFrom(outer); !ok || ctx.Value(contextKey{}) != "outer" {
+		t.Errorf("expected outermost context, got %v", ctx)
+	}
+
+	withoutContext := wrap.Error(errors.New("error"), "wrapped error")
+	if _, ok := ctxwrap.ContextFrom(withoutContext); ok {
+		t.Error("expected ContextFrom to return false for error without context")
+	}
+	if _, ok := ctxwrap.OutermostContextFrom(withoutContext); ok {
+		t.Error("expected OutermostContextFrom to return false for error without context")
+	}
+}
@@ -0,0 +1,67 @@
+package ctxwrap
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// MergedContext returns a context that combines all the contexts attached to the given error chain
+// (see [ContextFrom]). Its Value method consults every context, innermost first, so that values
+// attached to different layers are all available to the logger. Attributes added with [WithAttrs]
+// are merged as well: [ContextAttrs] on the merged context returns the attributes of every
+// context, innermost first, skipping attributes with keys that an inner context already has.
+//
+// Deadlines and cancellation are taken from the innermost context. If the error has a single
+// context, it is returned as is, and if it has none, MergedContext returns [context.Background].
+func MergedContext(err error) context.Context {
+	var contexts []context.Context
+	forEachContext(err, func(ctx context.Context) bool {
+		contexts = append(contexts, ctx)
+		return true
+	})
+
+	switch len(contexts) {
+	case 0:
+		return context.Background()
+	case 1:
+		return contexts[0]
+	}
+
+	slices.Reverse(contexts)
+	return mergedContext{Context: contexts[0], contexts: contexts}
+}
+
+// A context whose values are looked up in several contexts, innermost first. Embeds the innermost
+// context for deadlines and cancellation.
+type mergedContext struct {
+	context.Context
+	contexts []context.Context
+}
+
+func (ctx mergedContext) Value(key any) any {
+	if _, isAttrsKey := key.(contextAttrsKey); isAttrsKey {
+		return ctx.mergedAttrs()
+	}
+
+	for _, layerCtx := range ctx.contexts {
+		if value := layerCtx.Value(key); value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+func (ctx mergedContext) mergedAttrs() []slog.Attr {
+	var merged []slog.Attr
+	for _, layerCtx := range ctx.contexts {
+		for _, attr := range ContextAttrs(layerCtx) {
+			if !slices.ContainsFunc(merged, func(existing slog.Attr) bool {
+				return existing.Key == attr.Key
+			}) {
+				merged = append(merged, attr)
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,44 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+type otherContextKey struct{}
+
+func TestMergedContext(t *testing.T) {
+	outerCtx := context.WithValue(context.Background(), contextKey{}, "outer")
+	outerCtx = context.WithValue(outerCtx, otherContextKey{}, "outer only")
+	outerCtx = ctxwrap.WithAttrs(outerCtx, "request_id", "abc", "user_id", 1)
+	innerCtx := context.WithValue(context.Background(), contextKey{}, "inner")
+	innerCtx = ctxwrap.WithAttrs(innerCtx, "user_id", 2)
+
+	inner := ctxwrap.Error(innerCtx, errors.New("error"), "inner wrapped error")
+	outer := ctxwrap.Error(outerCtx, wrap.Error(inner, "middle wrapped error"), "outer")
+
+	merged := ctxwrap.MergedContext(outer)
+	if value := merged.Value(contextKey{}); value != "inner" {
+		t.Errorf("expected innermost value to take precedence, got %v", value)
+	}
+	if value := merged.Value(otherContextKey{}); value != "outer only" {
+		t.Errorf("expected value from outer context, got %v", value)
+	}
+
+	assertEqualAttrs(t, ctxwrap.ExtractAttrs(outer), []slog.Attr{
+		slog.Int("user_id", 2),
+		slog.String("request_id", "abc"),
+	})
+
+	if ctxwrap.MergedContext(inner) != innerCtx {
+		t.Error("expected single attached context to be returned as is")
+	}
+	if ctxwrap.MergedContext(errors.New("error")) != context.Background() {
+		t.Error("expected background context for error without context")
+	}
+}
@@ -0,0 +1,41 @@
+package ctxwrap
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// MissingContextKey is the log attribute key used to tag errors created without a request context,
+// if enabled with [SetTagMissingContext].
+const MissingContextKey = "ctx"
+
+var tagMissingContext atomic.Bool
+
+// SetTagMissingContext sets whether errors created by this package should be tagged with a log
+// attribute when the given context is nil, [context.Background] or [context.TODO]. The attribute
+// has the key [MissingContextKey], and the value "nil", "background" or "todo". This lets you find
+// code paths that don't propagate request contexts properly, by querying your logs for the
+// attribute. It is disabled by default.
+func SetTagMissingContext(tag bool) {
+	tagMissingContext.Store(tag)
+}
+
+// Returns the attribute to tag errors created from the given context with, if tagging is enabled
+// and the context is a fallback context.
+func missingContextAttr(ctx context.Context) (attr slog.Attr, ok bool) {
+	if !tagMissingContext.Load() {
+		return slog.Attr{}, false
+	}
+
+	switch ctx {
+	case nil:
+		return slog.String(MissingContextKey, "nil"), true
+	case context.Background():
+		return slog.String(MissingContextKey, "background"), true
+	case context.TODO():
+		return slog.String(MissingContextKey, "todo"), true
+	default:
+		return slog.Attr{}, false
+	}
+}
@@ -0,0 +1,45 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+func TestTagMissingContext(t *testing.T) {
+	ctxwrap.SetTagMissingContext(true)
+	defer ctxwrap.SetTagMissingContext(false)
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		expected []slog.Attr
+	}{
+		{"nil", nil, []slog.Attr{slog.String(ctxwrap.MissingContextKey, "nil")}},
+		{
+			"background",
+			context.Background(),
+			[]slog.Attr{slog.String(ctxwrap.MissingContextKey, "background")},
+		},
+		{"todo", context.TODO(), []slog.Attr{slog.String(ctxwrap.MissingContextKey, "todo")}},
+		{"request context", ctxwrap.WithRequestID(context.Background(), "abc"), []slog.Attr{
+			slog.String(wrap.RequestIDKey, "abc"),
+		}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ctxwrap.Error(testCase.ctx, errors.New("error"), "wrapped error")
+			assertEqualAttrs(t, wrap.Attrs(err), testCase.expected)
+		})
+	}
+}
+
+func TestMissingContextNotTaggedByDefault(t *testing.T) {
+	err := ctxwrap.Error(context.Background(), errors.New("error"), "wrapped error")
+	assertEqualAttrs(t, wrap.Attrs(err), nil)
+}
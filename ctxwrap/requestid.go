@@ -0,0 +1,24 @@
+package ctxwrap
+
+import (
+	"context"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the given context with the given request ID. Errors created by
+// this package from the context (or its children) include the request ID as a log attribute, with
+// the key [wrap.RequestIDKey]. Use [wrap.RequestID] to read it back from an error.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID of the given context, if one was set with [WithRequestID].
+func RequestID(ctx context.Context) (requestID string, ok bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	requestID, ok = ctx.Value(requestIDKey{}).(string)
+	return requestID, ok
+}
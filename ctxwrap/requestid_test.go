@@ -0,0 +1,91 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+func TestWithRequestID(t *testing.T) {
+	ctx := ctxwrap.WithRequestID(context.Background(), "request-1")
+
+	inner := ctxwrap.ErrorWithAttrs(ctx, errors.New("error"), "inner wrapped error", "attempt", 2)
+	outer := wrap.Error(inner, "outer wrapped error")
+
+	requestID, ok := wrap.RequestID(outer)
+	if !ok || requestID != "request-1" {
+		t.Errorf("unexpected request ID; got '%s' (found: %t), want 'request-1'", requestID, ok)
+	}
+
+	assertEqualAttrs(
+		t,
+		wrap.Attrs(outer),
+		[]slog.Attr{slog.Int("attempt", 2), slog.String("request_id", "request-1")},
+	)
+
+	wrapped := ctxwrap.Errors(ctx, "wrapped errors", errors.New("error 1"), errors.New("error 2"))
+	if requestID, _ := wrap.RequestID(wrapped); requestID != "request-1" {
+		t.Errorf("unexpected request ID for wrapped errors; got '%s', want 'request-1'", requestID)
+	}
+}
+
+func TestNestedRequestID(t *testing.T) {
+	ctx := ctxwrap.WithRequestID(context.Background(), "request-1")
+
+	inner := ctxwrap.ErrorWithAttrs(ctx, errors.New("error"), "inner wrapped error", "attempt", 2)
+	middle := ctxwrap.Errors(ctx, "middle wrapped errors", inner, errors.New("error 2"))
+	outer := ctxwrap.ErrorWithAttrs(ctx, middle, "outer wrapped error", "user_id", 1)
+
+	assertEqualAttrs(
+		t,
+		wrap.Attrs(outer),
+		[]slog.Attr{
+			slog.Int("user_id", 1),
+			slog.String("request_id", "request-1"),
+			slog.Int("attempt", 2),
+		},
+	)
+
+	checked := ctxwrap.CheckContext(canceledContext(ctx), "interrupted")
+	outer = ctxwrap.Error(ctx, checked, "outer wrapped error")
+	assertEqualAttrs(t, wrap.Attrs(outer), []slog.Attr{slog.String("request_id", "request-1")})
+}
+
+func TestNestedDifferentRequestIDs(t *testing.T) {
+	inner := ctxwrap.Error(
+		ctxwrap.WithRequestID(context.Background(), "request-1"),
+		errors.New("error"),
+		"inner wrapped error",
+	)
+	outer := ctxwrap.Error(
+		ctxwrap.WithRequestID(context.Background(), "request-2"),
+		inner,
+		"outer wrapped error",
+	)
+
+	assertEqualAttrs(
+		t,
+		wrap.Attrs(outer),
+		[]slog.Attr{
+			slog.String("request_id", "request-2"),
+			slog.String("request_id", "request-1"),
+		},
+	)
+}
+
+func canceledContext(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	cancel()
+	return ctx
+}
+
+func TestWithoutRequestID(t *testing.T) {
+	err := ctxwrap.Error(context.Background(), errors.New("error"), "wrapped error")
+	if _, ok := wrap.RequestID(err); ok {
+		t.Error("expected RequestID to return false for error from context without request ID")
+	}
+}
@@ -0,0 +1,84 @@
+package ctxwrap
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+)
+
+var snapshotContexts atomic.Bool
+
+// SetSnapshotContexts sets whether errors created by this package should store a snapshot of the
+// given context (see [Snapshot]) instead of the context itself. Retaining the context keeps all its
+// values alive for as long as the error is, which pins request-scoped objects in memory when errors
+// outlive their requests (e.g. when they are cached or reported asynchronously). It is disabled by
+// default.
+//
+// To snapshot the context for a single error, pass the context through [Snapshot] instead.
+func SetSnapshotContexts(snapshot bool) {
+	snapshotContexts.Store(snapshot)
+}
+
+// Snapshot returns a context that holds copies of the values that this package reads from the
+// given context when an error is logged: the attributes added with [WithAttrs], the attributes
+// returned by registered extractors (see [RegisterExtractor]) and the request ID (see
+// [WithRequestID]). All other values are dropped, and the snapshot is never canceled, so the given
+// context can be garbage collected. Use it when creating an error that may outlive its request:
+//
+//	err = ctxwrap.Error(ctxwrap.Snapshot(ctx), err, "failed to process job")
+//
+// Extractors run when the snapshot is taken rather than when the error is logged. Their attributes
+// are included in [ContextAttrs] of the snapshot, so extractors must return no attributes for
+// contexts without the values they read (which is the case for typical extractors).
+//
+// Nil, [context.Background] and [context.TODO] hold no values, and are returned as is.
+func Snapshot(ctx context.Context) context.Context {
+	switch ctx {
+	case nil, context.Background(), context.TODO():
+		return ctx
+	}
+	if _, isSnapshot := ctx.(snapshotContext); isSnapshot {
+		return ctx
+	}
+
+	requestID, hasRequestID := RequestID(ctx)
+	return snapshotContext{
+		Context:      context.Background(),
+		attrs:        slices.Clip(extractAttrs(ctx)),
+		requestID:    requestID,
+		hasRequestID: hasRequestID,
+	}
+}
+
+// Returns the context to store on a new error: a snapshot if enabled with SetSnapshotContexts, or
+// the given context otherwise.
+func contextToStore(ctx context.Context) context.Context {
+	if snapshotContexts.Load() {
+		return Snapshot(ctx)
+	}
+	return ctx
+}
+
+// A context that answers lookups of this package's context keys from values copied by Snapshot.
+// Embeds context.Background for deadlines and cancellation.
+type snapshotContext struct {
+	context.Context
+	attrs        []slog.Attr
+	requestID    string
+	hasRequestID bool
+}
+
+func (ctx snapshotContext) Value(key any) any {
+	switch key.(type) {
+	case contextAttrsKey:
+		if len(ctx.attrs) != 0 {
+			return ctx.attrs
+		}
+	case requestIDKey:
+		if ctx.hasRequestID {
+			return ctx.requestID
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package ctxwrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = context.WithValue(ctx, contextKey{}, "value")
+	ctx = ctxwrap.WithAttrs(ctx, "user_id", 1)
+	ctx = ctxwrap.WithRequestID(ctx, "abc")
+
+	snapshot := ctxwrap.Snapshot(ctx)
+	if snapshot.Value(contextKey{}) != nil {
+		t.Error("expected snapshot to drop other context values")
+	}
+	if _, hasDeadline := snapshot.Deadline(); hasDeadline {
+		t.Error("expected snapshot to have no deadline")
+	}
+	if requestID, _ := ctxwrap.RequestID(snapshot); requestID != "abc" {
+		t.Errorf("expected snapshot to keep request ID, got %q", requestID)
+	}
+
+	err := ctxwrap.Error(snapshot, errors.New("error"), "wrapped error")
+	assertEqualAttrs(t, ctxwrap.ExtractAttrs(err), []slog.Attr{slog.Int("user_id", 1)})
+	assertEqualAttrs(t, wrap.Attrs(err), []slog.Attr{slog.String(wrap.RequestIDKey, "abc")})
+
+	if ctxwrap.Snapshot(context.Background()) != context.Background() {
+		t.Error("expected background context to be returned as is")
+	}
+}
+
+func TestSetSnapshotContexts(t *testing.T) {
+	ctxwrap.SetSnapshotContexts(true)
+	defer ctxwrap.SetSnapshotContexts(false)
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	ctx = ctxwrap.WithAttrs(ctx, "user_id", 1)
+
+	for _, err := range []error{
+		ctxwrap.Error(ctx, errors.New("error"), "wrapped error"),
+		ctxwrap.Errors(ctx, "wrapped errors", errors.New("error 1"), errors.New("error 2")),
+	} {
+		stored, _ := ctxwrap.ContextFrom(err)
+		if stored.Value(contextKey{}) != nil {
+			t.Error("expected error to store a snapshot instead of the context")
+		}
+		assertEqualAttrs(t, ctxwrap.ExtractAttrs(err), []slog.Attr{slog.Int("user_id", 1)})
+	}
+}
@@ -0,0 +1,49 @@
+package wrap
+
+import (
+	"errors"
+)
+
+// WithData wraps the given error with a message for context, and attaches the given typed data to
+// it. The data can be retrieved further up the stack with [GetData], using the same type parameter.
+// Use this to pass a structured payload (such as a validation result) along with an error, without
+// defining a custom error type for it. The data is not included in the error string.
+//
+// Example:
+//
+//	type ValidationResult struct{ InvalidFields []string }
+//
+//	err := wrap.WithData(errors.New("invalid input"), "failed to create user", ValidationResult{
+//		InvalidFields: []string{"email"},
+//	})
+//	// Further up the stack:
+//	if result, ok := wrap.GetData[ValidationResult](err); ok {
+//		fmt.Println(result.InvalidFields) // [email]
+//	}
+//
+// The returned error implements the Unwrap method from the standard errors package, so it works
+// with [errors.Is] and [errors.As].
+func WithData[T any](wrapped error, message string, data T) error {
+	return runWrapHook(&dataError[T]{
+		wrappedError: wrappedError{wrapped: wrapped, message: message, caller: recordCaller()},
+		data:         data,
+	})
+}
+
+// GetData returns the data of type T attached to the given error or the errors it wraps with
+// [WithData], if any. The type must match exactly (an interface type only matches data attached
+// with that same interface type parameter). If there are several values of the type in the chain,
+// the outermost one is returned.
+func GetData[T any](err error) (data T, ok bool) {
+	var dataErr *dataError[T]
+	if errors.As(err, &dataErr) {
+		return dataErr.data, true
+	}
+	return data, false
+}
+
+// Uses a pointer receiver, so that the error stays comparable even if T is not.
+type dataError[T any] struct {
+	wrappedError
+	data T
+}
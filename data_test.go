@@ -0,0 +1,57 @@
+package wrap_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+type validationResult struct {
+	InvalidFields []string
+}
+
+func TestWithData(t *testing.T) {
+	err := errors.New("invalid input")
+	inner := wrap.WithData(err, "failed to validate user", validationResult{
+		InvalidFields: []string{"email", "username"},
+	})
+	outer := wrap.Error(inner, "failed to create user")
+
+	expected := `failed to create user
+- failed to validate user
+- invalid input`
+
+	assertEqualErrorStrings(t, outer, expected)
+
+	result, ok := wrap.GetData[validationResult](outer)
+	if !ok || !slices.Equal(result.InvalidFields, []string{"email", "username"}) {
+		t.Errorf("unexpected data; got (%+v, %t)", result, ok)
+	}
+	if !errors.Is(outer, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+	if !errors.Is(outer, inner) {
+		t.Error("expected errors.Is to return true for error with non-comparable data")
+	}
+}
+
+func TestGetDataMatchesType(t *testing.T) {
+	inner := wrap.WithData(errors.New("error"), "inner wrapped error", 1)
+	outer := wrap.WithData(inner, "outer wrapped error", "outer")
+	outermost := wrap.WithData(outer, "outermost wrapped error", 2)
+
+	if data, ok := wrap.GetData[int](outermost); !ok || data != 2 {
+		t.Errorf("expected outermost int data, got (%d, %t)", data, ok)
+	}
+	if data, ok := wrap.GetData[string](outermost); !ok || data != "outer" {
+		t.Errorf("expected string data, got (%q, %t)", data, ok)
+	}
+	if _, ok := wrap.GetData[validationResult](outermost); ok {
+		t.Error("expected GetData to return false for type not in chain")
+	}
+	if _, ok := wrap.GetData[int](errors.New("error")); ok {
+		t.Error("expected GetData to return false for error without data")
+	}
+}
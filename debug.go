@@ -0,0 +1,80 @@
+package wrap
+
+import (
+	"log/slog"
+)
+
+// DebugAttr returns a log attribute that is only meant for debug logging, for deep diagnostics
+// (such as full request payloads) that would make INFO and ERROR logs noisy. Attach it to errors
+// like any other attribute:
+//
+//	err = wrap.ErrorWithAttrs(err, "failed to parse response", wrap.DebugAttr("body", body))
+//
+// Consumers that are aware of debug attributes (such as [hermannm.dev/wrap/wrapslog.AddError], or
+// custom consumers using [AttrsForLevel]) only emit the attribute when logging at DEBUG level.
+// Other consumers resolve the value like any [slog.LogValuer], so the attribute is never lost.
+func DebugAttr(key string, value any) slog.Attr {
+	return slog.Any(key, debugValue{value: slog.AnyValue(value)})
+}
+
+// IsDebugAttr returns whether the given attribute was created with [DebugAttr].
+func IsDebugAttr(attr slog.Attr) bool {
+	_, ok := debugValueOf(attr.Value)
+	return ok
+}
+
+// AttrsForLevel returns the structured log attributes of the given error (like [Attrs]), for a
+// logger at the given level. Debug attributes (see [DebugAttr]) are only included if the level is
+// [slog.LevelDebug] or lower, and their values are resolved.
+func AttrsForLevel(err error, level slog.Level) []slog.Attr {
+	return FilterDebugAttrs(Attrs(err), level <= slog.LevelDebug)
+}
+
+// FilterDebugAttrs returns the given attributes without debug attributes (see [DebugAttr]), or with
+// their values resolved if includeDebug is true. It is meant for consumers that get attributes from
+// other sources than [Attrs], and returns the given slice if there are no debug attributes.
+func FilterDebugAttrs(attrs []slog.Attr, includeDebug bool) []slog.Attr {
+	var filtered []slog.Attr
+	for i, attr := range attrs {
+		value, isDebug := debugValueOf(attr.Value)
+		if !isDebug {
+			if filtered != nil {
+				filtered = append(filtered, attr)
+			}
+			continue
+		}
+
+		// Copies the preceding attributes on the first debug attribute, so the given slice is
+		// never modified
+		if filtered == nil {
+			filtered = make([]slog.Attr, i, len(attrs))
+			copy(filtered, attrs[:i])
+		}
+		if includeDebug {
+			filtered = append(filtered, slog.Attr{Key: attr.Key, Value: value})
+		}
+	}
+
+	if filtered == nil {
+		return attrs
+	}
+	return filtered
+}
+
+// Marks an attribute value as debug-only. Implements slog.LogValuer, so that consumers that are not
+// aware of debug attributes still log the value.
+type debugValue struct {
+	value slog.Value
+}
+
+func (debug debugValue) LogValue() slog.Value {
+	return debug.value
+}
+
+func debugValueOf(value slog.Value) (inner slog.Value, ok bool) {
+	if value.Kind() != slog.KindLogValuer {
+		return slog.Value{}, false
+	}
+	debug, ok := value.LogValuer().(debugValue)
+	return debug.value, ok
+}
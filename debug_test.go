@@ -0,0 +1,58 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestDebugAttr(t *testing.T) {
+	err := wrap.ErrorWithAttrs(
+		errors.New("unexpected token"),
+		"failed to parse response",
+		"status", 200,
+		wrap.DebugAttr("body", "{invalid"),
+	)
+
+	attrs := wrap.Attrs(err)
+	if len(attrs) != 2 || wrap.IsDebugAttr(attrs[0]) || !wrap.IsDebugAttr(attrs[1]) {
+		t.Errorf("expected only the second attribute to be a debug attribute, got %v", attrs)
+	}
+
+	assertEqualAttrSlices(
+		t,
+		wrap.AttrsForLevel(err, slog.LevelError),
+		[]slog.Attr{slog.Int("status", 200)},
+	)
+	assertEqualAttrSlices(
+		t,
+		wrap.AttrsForLevel(err, slog.LevelDebug),
+		[]slog.Attr{slog.Int("status", 200), slog.String("body", "{invalid")},
+	)
+}
+
+func TestDebugAttrWithSanitizer(t *testing.T) {
+	wrap.SetAttrSanitizer(wrap.NormalizeAttrValue)
+	defer wrap.SetAttrSanitizer(nil)
+
+	err := wrap.NewErrorWithAttrs("error", wrap.DebugAttr("cause", errors.New("cause")))
+
+	attrs := wrap.Attrs(err)
+	if len(attrs) != 1 || !wrap.IsDebugAttr(attrs[0]) {
+		t.Fatalf("expected sanitized attribute to stay a debug attribute, got %v", attrs)
+	}
+	assertEqualAttrSlices(
+		t,
+		wrap.FilterDebugAttrs(attrs, true),
+		[]slog.Attr{slog.String("cause", "cause")},
+	)
+}
+
+func TestFilterDebugAttrsWithoutDebugAttrs(t *testing.T) {
+	attrs := []slog.Attr{slog.String("key", "value")}
+	if filtered := wrap.FilterDebugAttrs(attrs, false); &filtered[0] != &attrs[0] {
+		t.Error("expected attributes without debug attributes to be returned as-is")
+	}
+}
@@ -0,0 +1,37 @@
+package wrap
+
+// Defer wraps the error pointed to by errPtr with a message for context, if the error is non-nil.
+// It is meant to be called in a defer statement with a pointer to a named error return value, to
+// wrap every error returned from a function in one place:
+//
+//	func processOrder(order Order) (err error) {
+//		defer wrap.Defer(&err, "failed to process order")
+//
+//		if err := validateOrder(order); err != nil {
+//			return err
+//		}
+//		return saveOrder(order)
+//	}
+//
+// If the error is nil, it is left unchanged.
+func Defer(errPtr *error, message string) {
+	if *errPtr != nil {
+		*errPtr = Error(*errPtr, message)
+	}
+}
+
+// Deferf wraps the error pointed to by errPtr with a formatted message for context, if the error is
+// non-nil. It works like [Defer], but forwards the given message format and args to [fmt.Sprintf]
+// to construct the message (like [Errorf]).
+//
+// Example:
+//
+//	func processOrder(orderID int) (err error) {
+//		defer wrap.Deferf(&err, "failed to process order %d", orderID)
+//		// ...
+//	}
+func Deferf(errPtr *error, messageFormat string, formatArgs ...any) {
+	if *errPtr != nil {
+		*errPtr = Errorf(*errPtr, messageFormat, formatArgs...)
+	}
+}
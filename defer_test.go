@@ -0,0 +1,48 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestDefer(t *testing.T) {
+	processOrder := func(fail bool) (err error) {
+		defer wrap.Defer(&err, "failed to process order")
+
+		if fail {
+			return errors.New("order not found")
+		}
+		return nil
+	}
+
+	expected := `failed to process order
+- order not found`
+
+	assertEqualErrorStrings(t, processOrder(true), expected)
+
+	if err := processOrder(false); err != nil {
+		t.Errorf("expected nil error to be left unchanged, got %v", err)
+	}
+}
+
+func TestDeferf(t *testing.T) {
+	processOrder := func(orderID int, fail bool) (err error) {
+		defer wrap.Deferf(&err, "failed to process order %d", orderID)
+
+		if fail {
+			return errors.New("order not found")
+		}
+		return nil
+	}
+
+	expected := `failed to process order 123
+- order not found`
+
+	assertEqualErrorStrings(t, processOrder(123, true), expected)
+
+	if err := processOrder(123, false); err != nil {
+		t.Errorf("expected nil error to be left unchanged, got %v", err)
+	}
+}
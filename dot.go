@@ -0,0 +1,101 @@
+package wrap
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatDOT renders the given error tree as a graph in the DOT language used by Graphviz. Every
+// wrapping message becomes a node with edges to the errors it wraps, so multi-error fan-outs from
+// [Errors] show up as branches. This is useful for visualizing large aggregated failures, such as
+// those from parallel jobs.
+//
+// Example:
+//
+//	err1 := errors.New("username too long")
+//	err2 := errors.New("invalid email")
+//	wrapped := wrap.Errors("user creation failed", err1, err2)
+//	fmt.Println(wrap.FormatDOT(wrapped))
+//	// digraph {
+//	// 	n0 [label="user creation failed"];
+//	// 	n1 [label="username too long"];
+//	// 	n0 -> n1;
+//	// 	n2 [label="invalid email"];
+//	// 	n0 -> n2;
+//	// }
+//
+// If callers are recorded with [SetRecordCallers], the label of each wrapping layer also includes
+// the package path where the layer was wrapped, on a separate line in parentheses.
+//
+// The output can be piped to Graphviz, e.g. `dot -Tsvg -o error.svg`.
+func FormatDOT(err error) string {
+	var builder dotBuilder
+	builder.WriteString("digraph {\n")
+	if err != nil {
+		builder.writeNode(err)
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+type dotBuilder struct {
+	strings.Builder
+	nodeCount int
+}
+
+// Writes a node for the given error and its wrapped errors, and returns the ID of the node.
+func (builder *dotBuilder) writeNode(err error) string {
+	err = unwrapMarkers(err)
+
+	id := "n" + strconv.Itoa(builder.nodeCount)
+	builder.nodeCount++
+
+	switch err := err.(type) {
+	case wrappingError:
+		builder.writeNodeLabel(id, withPackage(err.WrappingMessage(), err))
+		builder.writeEdge(id, builder.writeNode(err.Unwrap()))
+	case wrappingErrors:
+		builder.writeNodeLabel(id, withPackage(err.WrappingMessage(), err))
+		for _, wrappedErr := range err.Unwrap() {
+			builder.writeEdge(id, builder.writeNode(wrappedErr))
+		}
+	default:
+		builder.writeNodeLabel(id, leafMessage(err))
+	}
+
+	return id
+}
+
+// Appends the package where the given error was wrapped to the label, if recorded.
+func withPackage(label string, err error) string {
+	if pkg := errorPackage(err); pkg != "" {
+		return label + "\n(" + pkg + ")"
+	}
+	return label
+}
+
+func (builder *dotBuilder) writeNodeLabel(id string, label string) {
+	builder.WriteByte('\t')
+	builder.WriteString(id)
+	builder.WriteString(` [label="`)
+	for _, char := range label {
+		switch char {
+		case '"', '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(char)
+		case '\n':
+			builder.WriteString(`\n`)
+		default:
+			builder.WriteRune(char)
+		}
+	}
+	builder.WriteString("\"];\n")
+}
+
+func (builder *dotBuilder) writeEdge(fromID string, toID string) {
+	builder.WriteByte('\t')
+	builder.WriteString(fromID)
+	builder.WriteString(" -> ")
+	builder.WriteString(toID)
+	builder.WriteString(";\n")
+}
@@ -0,0 +1,50 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestFormatDOT(t *testing.T) {
+	err1 := errors.New("username too long")
+	err2 := errors.New(`invalid email "user@"`)
+	inner := wrap.Errors("user creation failed", err1, err2)
+	outer := wrap.Error(inner, "failed to register\nnew user")
+
+	expected := `digraph {
+	n0 [label="failed to register\nnew user"];
+	n1 [label="user creation failed"];
+	n2 [label="username too long"];
+	n1 -> n2;
+	n3 [label="invalid email \"user@\""];
+	n1 -> n3;
+	n0 -> n1;
+}
+`
+
+	assertEqualStrings(t, wrap.FormatDOT(outer), expected)
+}
+
+func TestFormatDOTNil(t *testing.T) {
+	assertEqualStrings(t, wrap.FormatDOT(nil), "digraph {\n}\n")
+}
+
+func assertEqualStrings(t *testing.T, actual string, expected string) {
+	t.Helper()
+
+	if actual != expected {
+		t.Errorf(`unexpected string
+got:
+----------------------------------------
+%s
+----------------------------------------
+
+want:
+----------------------------------------
+%s
+----------------------------------------
+`, actual, expected)
+	}
+}
@@ -0,0 +1,109 @@
+package wrap
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+var (
+	collapseDuplicates      atomic.Bool
+	collapseDuplicateErrors atomic.Bool
+)
+
+// SetCollapseDuplicateMessages sets whether the formatter should collapse consecutive layers with
+// the same message into one line. This is common when a helper and its caller both describe the
+// same failure:
+//
+//	wrap.SetCollapseDuplicateMessages(true)
+//	err := errors.New("connection refused")
+//	wrapped := wrap.Error(wrap.Error(err, "request failed"), "request failed")
+//	fmt.Println(wrapped)
+//	// request failed
+//	// - connection refused
+//
+// Only the error string is affected; [Walk], [Flatten] and [Trail] still return every layer. It is
+// disabled by default.
+func SetCollapseDuplicateMessages(enabled bool) {
+	collapseDuplicates.Store(enabled)
+}
+
+// Skips the layers at the start of the given wrapped error that have the same message as the layer
+// wrapping it, if enabled with SetCollapseDuplicateMessages. Multi-error layers are never skipped,
+// since their wrapped errors would otherwise lose their grouping. Returns false if the rest of the
+// chain is a duplicate, i.e. there is nothing left to write.
+func skipDuplicateMessages(message string, wrapped error) (remaining error, ok bool) {
+	if !collapseDuplicates.Load() {
+		return wrapped, true
+	}
+
+	for wrapped != nil {
+		unwrapped, remote := unwrapMarkersToRemote(wrapped)
+		if remote {
+			// Layers after the remote divider are not collapsed into the layer before it
+			return wrapped, true
+		}
+
+		switch err := unwrapped.(type) {
+		case wrappingError:
+			if err.WrappingMessage() != message {
+				return wrapped, true
+			}
+			wrapped = err.Unwrap()
+		case wrappingErrors:
+			return wrapped, true
+		default:
+			return wrapped, leafMessage(err) != message
+		}
+	}
+	return nil, false
+}
+
+// SetCollapseDuplicateErrors sets whether the formatter should collapse identical errors in the
+// list of a multi-error (such as from [Errors]) into one entry, followed by the number of times the
+// error occurred. Errors are identical if their error strings are the same. This keeps fan-out
+// operations that fail the same way for many inputs readable:
+//
+//	wrap.SetCollapseDuplicateErrors(true)
+//	errs := make([]error, 100)
+//	for i := range errs {
+//		errs[i] = wrap.Error(context.DeadlineExceeded, "shard query failed")
+//	}
+//	fmt.Println(wrap.Errors("search failed", errs...))
+//	// search failed
+//	// - shard query failed (×100)
+//	//   - context deadline exceeded
+//
+// Only the error string is affected; the multi-error still wraps every error, for [errors.Is],
+// [Walk] and [Summarize]. It is disabled by default.
+func SetCollapseDuplicateErrors(enabled bool) {
+	collapseDuplicateErrors.Store(enabled)
+}
+
+// Groups identical errors (by error string) in the given list, keeping the first occurrence of each
+// in its original position. Returns the grouped errors, and the number of occurrences of each.
+func groupDuplicateErrors(errs []error) (grouped []error, counts []int) {
+	indexes := make(map[string]int, len(errs))
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		message := err.Error()
+		if index, ok := indexes[message]; ok {
+			counts[index]++
+			continue
+		}
+		indexes[message] = len(grouped)
+		grouped = append(grouped, err)
+		counts = append(counts, 1)
+	}
+	return grouped, counts
+}
+
+func (builder *errorBuilder) writeDuplicateCount(count int) {
+	if count > 1 {
+		builder.WriteString(" (×")
+		builder.WriteString(strconv.Itoa(count))
+		builder.WriteByte(')')
+	}
+}
@@ -0,0 +1,93 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestCollapseDuplicateMessages(t *testing.T) {
+	wrap.SetCollapseDuplicateMessages(true)
+	defer wrap.SetCollapseDuplicateMessages(false)
+
+	err := errors.New("connection refused")
+	inner := wrap.ErrorWithCode(wrap.Error(err, "request failed"), "UNAVAILABLE", "request failed")
+	outer := wrap.Error(wrap.Error(inner, "request failed"), "failed to fetch user")
+
+	expected := `failed to fetch user
+- request failed
+- connection refused`
+
+	assertEqualErrorStrings(t, outer, expected)
+
+	if layers := wrap.Flatten(outer); len(layers) != 5 {
+		t.Errorf("expected Flatten to keep all 5 layers, got %d", len(layers))
+	}
+}
+
+func TestCollapseDuplicateMessagesWithLeaf(t *testing.T) {
+	wrap.SetCollapseDuplicateMessages(true)
+	defer wrap.SetCollapseDuplicateMessages(false)
+
+	err := wrap.Errors(
+		"validation failed",
+		wrap.Error(errors.New("invalid email"), "invalid email"),
+		errors.New("invalid phone number"),
+	)
+	wrapped := wrap.Error(wrap.Error(err, "validation failed"), "request failed")
+
+	expected := `request failed
+- validation failed
+- validation failed
+  - invalid email
+  - invalid phone number`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
+
+func TestCollapseDuplicateMessagesDisabled(t *testing.T) {
+	err := wrap.Error(wrap.Error(errors.New("error"), "request failed"), "request failed")
+
+	expected := `request failed
+- request failed
+- error`
+
+	assertEqualErrorStrings(t, err, expected)
+}
+
+func TestCollapseDuplicateErrors(t *testing.T) {
+	wrap.SetCollapseDuplicateErrors(true)
+	defer wrap.SetCollapseDuplicateErrors(false)
+
+	timeout := errors.New("timeout")
+	errs := []error{
+		wrap.Error(timeout, "shard query failed"),
+		errors.New("shard not found"),
+		wrap.Error(timeout, "shard query failed"),
+		wrap.Error(timeout, "shard query failed"),
+	}
+	err := wrap.Error(wrap.Errors("search failed", errs...), "request failed")
+
+	expected := `request failed
+- search failed
+  - shard query failed (×3)
+    - timeout
+  - shard not found`
+
+	assertEqualErrorStrings(t, err, expected)
+
+	allSame := wrap.Errors("search failed", timeout, timeout)
+	assertEqualErrorStrings(t, allSame, "search failed\n- timeout (×2)")
+
+	single := wrap.Errors("search failed", errs[0], errs[2])
+	expected = `search failed
+- shard query failed (×2)
+  - timeout`
+
+	assertEqualErrorStrings(t, single, expected)
+
+	if layers := wrap.Flatten(err); len(layers) != 9 {
+		t.Errorf("expected Flatten to keep all 9 layers, got %d", len(layers))
+	}
+}
@@ -0,0 +1,41 @@
+package wrap
+
+// Each wraps every non-nil error in the given slice with a per-item message, and returns the
+// wrapped errors (leaving out nil errors). This is for adding item context to the results of batch
+// processing before combining them with [Errors]. The message of each item is created by
+// forwarding messageFormat and the args returned by formatArgs for the item's index to [Errorf]. If
+// formatArgs is nil, the index itself is used as the only arg.
+//
+// Example:
+//
+//	errs := []error{nil, errors.New("invalid email"), nil, errors.New("username too long")}
+//	wrapped := wrap.Errors("batch failed", wrap.Each(errs, "item %d failed", nil)...)
+//	fmt.Println(wrapped)
+//	// batch failed
+//	// - item 1 failed
+//	//   - invalid email
+//	// - item 3 failed
+//	//   - username too long
+//
+// Use formatArgs to identify items by something other than their index:
+//
+//	wrap.Each(errs, "failed to import user %q", func(index int) []any {
+//		return []any{users[index].Name}
+//	})
+func Each(errs []error, messageFormat string, formatArgs func(index int) []any) []error {
+	var wrapped []error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		var args []any
+		if formatArgs == nil {
+			args = []any{i}
+		} else {
+			args = formatArgs(i)
+		}
+		wrapped = append(wrapped, Errorf(err, messageFormat, args...))
+	}
+	return wrapped
+}
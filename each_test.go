@@ -0,0 +1,46 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestEach(t *testing.T) {
+	err1 := errors.New("invalid email")
+	err2 := errors.New("username too long")
+	errs := []error{nil, err1, nil, err2}
+
+	wrapped := wrap.Errors("batch failed", wrap.Each(errs, "item %d failed", nil)...)
+
+	expected := `batch failed
+- item 1 failed
+  - invalid email
+- item 3 failed
+  - username too long`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if !errors.Is(wrapped, err1) || !errors.Is(wrapped, err2) {
+		t.Error("expected errors.Is to return true for wrapped items")
+	}
+}
+
+func TestEachWithFormatArgs(t *testing.T) {
+	users := []string{"hermannm", "gopher"}
+	errs := []error{errors.New("username taken"), errors.New("invalid email")}
+
+	wrapped := wrap.Each(errs, "failed to import user %q", func(index int) []any {
+		return []any{users[index]}
+	})
+
+	if len(wrapped) != 2 {
+		t.Fatalf("expected 2 wrapped errors, got %d", len(wrapped))
+	}
+	assertEqualErrorStrings(t, wrapped[1], "failed to import user \"gopher\"\n- invalid email")
+
+	if len(wrap.Each([]error{nil, nil}, "item %d failed", nil)) != 0 {
+		t.Error("expected no errors for slice of nil errors")
+	}
+}
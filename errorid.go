@@ -0,0 +1,89 @@
+package wrap
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// ErrorIDKey is the log attribute key for error IDs attached with [AddErrorID].
+const ErrorIDKey = "error_id"
+
+// AddErrorID stamps the given error with a unique ID, without changing how the error is displayed.
+// The ID is a ULID (a 26-character, time-sortable identifier), which is short enough to show to
+// users as a reference code, so that support can correlate a customer report with the exact log
+// entry. The ID is logged with the key [ErrorIDKey], and can be retrieved with [ErrorID].
+//
+// If the error (or an error it wraps) already has an ID, the error is returned unchanged, so it is
+// safe to stamp errors both where they are created and where they are reported. If the given error
+// is nil, AddErrorID returns nil.
+func AddErrorID(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := ErrorID(err); ok {
+		return err
+	}
+	return withNetError(&errorIDError{wrapped: err, id: newULID(time.Now())})
+}
+
+// ErrorID returns the ID attached to the given error or the errors it wraps with [AddErrorID], if
+// any.
+func ErrorID(err error) (id string, ok bool) {
+	var idErr *errorIDError
+	if errors.As(err, &idErr) {
+		return idErr.id, true
+	}
+	return "", false
+}
+
+type errorIDError struct {
+	wrapped error
+	id      string
+}
+
+func (err *errorIDError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *errorIDError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *errorIDError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// LogAttrs returns the error ID as a structured log attribute, for logging libraries that look for
+// this method (such as [hermannm.dev/devlog/log]).
+func (err *errorIDError) LogAttrs() []slog.Attr {
+	return []slog.Attr{slog.String(ErrorIDKey, err.id)}
+}
+
+// Crockford's base32 alphabet, as used by ULIDs.
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Generates a ULID: a 48-bit millisecond timestamp followed by 80 random bits, encoded as 26
+// characters of Crockford's base32.
+func newULID(now time.Time) string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(now.UnixMilli())<<16)
+	// crypto/rand.Read never returns an error on supported platforms
+	rand.Read(id[6:])
+
+	// Encodes the 128 bits 5 at a time, from the most significant end. 26*5 = 130, so the first
+	// character only encodes the 3 most significant bits.
+	high := binary.BigEndian.Uint64(id[:8])
+	low := binary.BigEndian.Uint64(id[8:])
+
+	var encoded [26]byte
+	for i := len(encoded) - 1; i >= 0; i-- {
+		encoded[i] = crockfordBase32[low&0x1F]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+	return string(encoded[:])
+}
@@ -0,0 +1,54 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAddErrorID(t *testing.T) {
+	err := wrap.AddErrorID(wrap.Error(errors.New("error"), "wrapped error"))
+	wrapped := wrap.Error(err, "outer error")
+
+	assertEqualErrorStrings(t, wrapped, `outer error
+- wrapped error
+- error`)
+
+	id, ok := wrap.ErrorID(wrapped)
+	if !ok {
+		t.Fatal("expected error to have an ID")
+	}
+	if len(id) != 26 {
+		t.Errorf("expected 26-character ULID, got %q", id)
+	}
+	assertEqualAttrSlices(t, wrap.Attrs(wrapped), []slog.Attr{slog.String("error_id", id)})
+
+	if restamped := wrap.AddErrorID(wrapped); restamped != wrapped {
+		t.Error("expected error that already has an ID to be returned unchanged")
+	}
+}
+
+func TestErrorIDsAreUniqueAndSortable(t *testing.T) {
+	var previous string
+	for i := 0; i < 100; i++ {
+		id, _ := wrap.ErrorID(wrap.AddErrorID(errors.New("error")))
+		if id == previous {
+			t.Fatalf("expected unique IDs, got %q twice", id)
+		}
+		// The first 10 characters encode the timestamp
+		if previous != "" && id[:10] < previous[:10] {
+			t.Errorf("expected non-decreasing ID timestamps, got %q after %q", id, previous)
+		}
+		previous = id
+	}
+}
+
+func TestBuilderWithErrorID(t *testing.T) {
+	err := wrap.New(errors.New("error")).Message("wrapped error").With(wrap.WithErrorID()).Err()
+
+	if _, ok := wrap.ErrorID(err); !ok {
+		t.Error("expected WithErrorID to stamp the error with an ID")
+	}
+}
@@ -0,0 +1,53 @@
+// Package errschema defines the interfaces that structured errors implement, so that error types
+// and logging libraries from different modules can work together without depending on each other.
+// [hermannm.dev/wrap] implements these interfaces, and logging libraries such as
+// [hermannm.dev/devlog/log] consume them. The methods are matched structurally, so error types only
+// need to implement the methods, not import this package.
+//
+// The interfaces are stable: methods will not be added to or removed from them. New capabilities
+// are added as new interfaces.
+package errschema
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WrappedError is implemented by errors that wrap a single error with a message. Consumers should
+// display the wrapping message, followed by the wrapped error from Unwrap, instead of the full
+// error string (which also includes the wrapped error).
+type WrappedError interface {
+	error
+	// WrappingMessage returns the message of this layer, without the wrapped error.
+	WrappingMessage() string
+	// Unwrap returns the wrapped error, matching the signature expected by the [errors] package.
+	Unwrap() error
+}
+
+// WrappedErrors is implemented by errors that wrap multiple errors with a message. Consumers should
+// display the wrapping message, followed by a list of the wrapped errors from Unwrap.
+type WrappedErrors interface {
+	error
+	// WrappingMessage returns the message of this layer, without the wrapped errors.
+	WrappingMessage() string
+	// Unwrap returns the wrapped errors, matching the signature expected by the [errors] package.
+	Unwrap() []error
+}
+
+// ErrorWithAttrs is implemented by errors that carry structured log attributes. Consumers should
+// add the attributes of the error and all errors it wraps to the log record, outermost first.
+type ErrorWithAttrs interface {
+	error
+	// LogAttrs returns the log attributes attached to this layer, not including the attributes of
+	// wrapped errors.
+	LogAttrs() []slog.Attr
+}
+
+// ErrorWithContext is implemented by errors that carry the [context.Context] they were created in.
+// Consumers may extract log attributes (such as trace IDs) from the context when the error is
+// logged, even after the context has gone out of scope.
+type ErrorWithContext interface {
+	error
+	// Context returns the context attached to this layer.
+	Context() context.Context
+}
@@ -0,0 +1,31 @@
+package errschema_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+	"hermannm.dev/wrap/errschema"
+)
+
+func TestWrapImplementsSchema(t *testing.T) {
+	err := errors.New("error")
+
+	if _, ok := wrap.Error(err, "wrapped error").(errschema.WrappedError); !ok {
+		t.Error("expected wrap.Error to implement WrappedError")
+	}
+	if _, ok := wrap.Errors("wrapped errors", err, err).(errschema.WrappedErrors); !ok {
+		t.Error("expected wrap.Errors to implement WrappedErrors")
+	}
+	withAttrs := wrap.ErrorWithAttrs(err, "wrapped error", "key", "value")
+	if _, ok := withAttrs.(errschema.ErrorWithAttrs); !ok {
+		t.Error("expected wrap.ErrorWithAttrs to implement ErrorWithAttrs")
+	}
+	ctx := ctxwrap.WithRequestID(context.Background(), "abc")
+	withContext := ctxwrap.Error(ctx, err, "wrapped error")
+	if _, ok := withContext.(errschema.ErrorWithContext); !ok {
+		t.Error("expected ctxwrap.Error to implement ErrorWithContext")
+	}
+}
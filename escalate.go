@@ -0,0 +1,67 @@
+package wrap
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Raises the level of errors matching the predicate to at least the given level.
+type escalationRule struct {
+	predicate func(err error) bool
+	level     slog.Level
+}
+
+// The registered escalation rules. The list is copied on write, so that Severity can read it
+// without taking a lock.
+var (
+	escalationRules     atomic.Pointer[[]escalationRule]
+	escalationRulesLock sync.Mutex // Serializes writers
+)
+
+// EscalateWhen registers a rule that raises the severity of errors matching the given predicate to
+// at least the given level, as returned by [Severity]. This lets you encode severity policies in
+// one place, instead of scattering conditionals across handlers:
+//
+//	wrap.EscalateWhen(func(err error) bool {
+//		code, _ := wrap.CodeOf(err)
+//		internalCall := slices.ContainsFunc(wrap.Attrs(err), func(attr slog.Attr) bool {
+//			return attr.Key == "internal_call" && attr.Value.Equal(slog.BoolValue(true))
+//		})
+//		return code == "NOT_FOUND" && internalCall
+//	}, slog.LevelError)
+//
+// Rules only ever raise the severity, so an error matching several rules gets the highest of their
+// levels. It is safe to call concurrently, but is typically called at program startup.
+func EscalateWhen(predicate func(err error) bool, level slog.Level) {
+	escalationRulesLock.Lock()
+	defer escalationRulesLock.Unlock()
+
+	var newRules []escalationRule
+	if oldRules := escalationRules.Load(); oldRules != nil {
+		newRules = slices.Clone(*oldRules)
+	}
+	newRules = append(newRules, escalationRule{predicate: predicate, level: level})
+	escalationRules.Store(&newRules)
+}
+
+// Severity returns the log level that the given error should be reported with. This is the level
+// attached to the error (see [LevelOf]), or the given default level if there is none, raised by any
+// matching rules registered with [EscalateWhen]. Reporters and logging middleware should use this
+// to pick the level of errors, so that escalation rules apply everywhere.
+func Severity(err error, defaultLevel slog.Level) slog.Level {
+	level, ok := LevelOf(err)
+	if !ok {
+		level = defaultLevel
+	}
+
+	if rules := escalationRules.Load(); rules != nil {
+		for _, rule := range *rules {
+			if rule.level > level && rule.predicate(err) {
+				level = rule.level
+			}
+		}
+	}
+	return level
+}
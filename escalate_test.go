@@ -0,0 +1,45 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestEscalateWhen(t *testing.T) {
+	notFound := wrap.NewSentinel("not found", wrap.WithCode("ESCALATE_TEST_NOT_FOUND"))
+
+	wrap.EscalateWhen(func(err error) bool {
+		code, _ := wrap.CodeOf(err)
+		if code != "ESCALATE_TEST_NOT_FOUND" {
+			return false
+		}
+		for _, attr := range wrap.Attrs(err) {
+			if attr.Key == "internal_call" && attr.Value.Equal(slog.BoolValue(true)) {
+				return true
+			}
+		}
+		return false
+	}, slog.LevelError)
+
+	external := wrap.ErrorWithLevel(notFound, slog.LevelInfo, "user not found")
+	if level := wrap.Severity(external, slog.LevelError); level != slog.LevelInfo {
+		t.Errorf("expected attached level without escalation, got %v", level)
+	}
+
+	internal := wrap.ErrorWithAttrs(external, "failed to fetch user", "internal_call", true)
+	if level := wrap.Severity(internal, slog.LevelWarn); level != slog.LevelError {
+		t.Errorf("expected escalated level, got %v", level)
+	}
+
+	critical := wrap.ErrorWithLevel(internal, wrap.LevelCritical, "failed to sync users")
+	if level := wrap.Severity(critical, slog.LevelWarn); level != wrap.LevelCritical {
+		t.Errorf("expected escalation not to lower the level, got %v", level)
+	}
+
+	if level := wrap.Severity(errors.New("error"), slog.LevelWarn); level != slog.LevelWarn {
+		t.Errorf("expected default level for error without level, got %v", level)
+	}
+}
@@ -0,0 +1,38 @@
+package wrap
+
+// Find returns the first error in the given error tree that matches the given predicate, searching
+// depth-first from the outermost error (following both the single-error and multi-error Unwrap
+// methods from the standard errors package). It returns false if no error matches.
+//
+// Use this instead of repeated calls to [errors.As] when searching for causes by arbitrary
+// conditions, such as a database error with a specific code:
+//
+//	uniqueViolation, ok := wrap.Find(err, func(err error) bool {
+//		pgErr, ok := err.(*pgconn.PgError)
+//		return ok && pgErr.Code == "23505"
+//	})
+//
+// The predicate is called with every error in the tree, so use type assertions rather than
+// [errors.As] or [errors.Is] in it: those also match the errors that wrap a match, so the outermost
+// error would be returned.
+func Find(err error, predicate func(err error) bool) (found error, ok bool) {
+	forEachInChain(err, func(err error) {
+		if !ok && predicate(err) {
+			found, ok = err, true
+		}
+	})
+	return found, ok
+}
+
+// FindAll returns all errors in the given error tree that match the given predicate, in the same
+// order as searched by [Find]. This is useful for multi-error trees (such as the ones created by
+// [Errors]), where several branches may have matching causes.
+func FindAll(err error, predicate func(err error) bool) []error {
+	var found []error
+	forEachInChain(err, func(err error) {
+		if predicate(err) {
+			found = append(found, err)
+		}
+	})
+	return found
+}
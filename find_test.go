@@ -0,0 +1,45 @@
+package wrap_test
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestFind(t *testing.T) {
+	notFound := &fs.PathError{Op: "open", Path: "config.json", Err: fs.ErrNotExist}
+	denied := &fs.PathError{Op: "open", Path: "secrets.json", Err: fs.ErrPermission}
+	err := wrap.Error(
+		wrap.Errors("failed to load files", wrap.Error(notFound, "failed to load config"), denied),
+		"startup failed",
+	)
+
+	isPathErr := func(err error) bool {
+		_, ok := err.(*fs.PathError)
+		return ok
+	}
+
+	found, ok := wrap.Find(err, isPathErr)
+	if !ok || found != notFound {
+		t.Errorf("expected Find to return first path error, got (%v, %t)", found, ok)
+	}
+
+	if all := wrap.FindAll(err, isPathErr); !slices.Equal(all, []error{notFound, denied}) {
+		t.Errorf("expected FindAll to return both path errors, got %v", all)
+	}
+
+	isDenied := func(err error) bool {
+		pathErr, ok := err.(*fs.PathError)
+		return ok && errors.Is(pathErr.Err, fs.ErrPermission)
+	}
+	if found, _ := wrap.Find(err, isDenied); found != denied {
+		t.Errorf("expected Find to match predicate on fields, got %v", found)
+	}
+
+	if _, ok := wrap.Find(err, func(error) bool { return false }); ok {
+		t.Error("expected Find to return false when no error matches")
+	}
+}
@@ -0,0 +1,85 @@
+package wrap
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"strconv"
+)
+
+// Fingerprint returns a short, stable identifier for the given error tree, for grouping similar
+// errors in logs, metrics and error trackers. Errors with the same wrapping messages and the same
+// root causes get the same fingerprint.
+//
+// For messages created with [Errorf], the fingerprint is based on the format string rather than the
+// formatted message. This way, messages that embed IDs or other variable data still group together:
+//
+//	err := errors.New("user not found")
+//	wrapped1 := wrap.Errorf(err, "failed to fetch user with ID %d", 1)
+//	wrapped2 := wrap.Errorf(err, "failed to fetch user with ID %d", 2)
+//	fmt.Println(wrap.Fingerprint(wrapped1) == wrap.Fingerprint(wrapped2))
+//	// true
+//
+// Errors that don't come from this package are fingerprinted by their type and error string. The
+// fingerprint of a nil error is the empty string.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	hash := fnv.New64a()
+	writeFingerprint(hash, err)
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// Writes the parts of the given error that identify it to the given hash, separated by null bytes
+// to avoid collisions between different splits of the same text.
+func writeFingerprint(hash hash.Hash64, err error) {
+	err = unwrapMarkers(err)
+
+	switch err := err.(type) {
+	case wrappingError:
+		io.WriteString(hash, fingerprintMessage(err))
+		hash.Write([]byte{0})
+		writeFingerprint(hash, err.Unwrap())
+	case wrappingErrors:
+		io.WriteString(hash, fingerprintMessage(err))
+		hash.Write([]byte{0})
+		for _, wrappedErr := range err.Unwrap() {
+			writeFingerprint(hash, wrappedErr)
+		}
+		hash.Write([]byte{1}) // Marks the end of the list, to separate nested lists
+	case nil:
+		hash.Write([]byte{0})
+	default:
+		fmt.Fprintf(hash, "%T", err)
+		hash.Write([]byte{0})
+		io.WriteString(hash, leafFingerprintMessage(err))
+		hash.Write([]byte{0})
+	}
+}
+
+// Returns the message format string of the given wrapping error if it has one (i.e. it was created
+// with Errorf), or the wrapping message otherwise.
+func fingerprintMessage(err interface{ WrappingMessage() string }) string {
+	if formatted, ok := err.(interface{ messageFormatString() string }); ok {
+		if format := formatted.messageFormatString(); format != "" {
+			return format
+		}
+	}
+	return err.WrappingMessage()
+}
+
+// Returns the message format string of the given leaf error if it was created with NewErrorf, or
+// the error string otherwise.
+func leafFingerprintMessage(err error) string {
+	if leaf, ok := err.(*leafError); ok && leaf.messageFormat != "" {
+		return leaf.messageFormat
+	}
+	return err.Error()
+}
+
+func (err wrappedError) messageFormatString() string {
+	return err.messageFormat
+}
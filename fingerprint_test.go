@@ -0,0 +1,51 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestFingerprint(t *testing.T) {
+	err := errors.New("user not found")
+
+	fingerprint1 := wrap.Fingerprint(wrap.Errorf(err, "failed to fetch user with ID %d", 1))
+	fingerprint2 := wrap.Fingerprint(wrap.Errorf(err, "failed to fetch user with ID %d", 2))
+	if fingerprint1 != fingerprint2 {
+		t.Errorf(
+			"expected errors with same message format to have same fingerprint, got %s and %s",
+			fingerprint1,
+			fingerprint2,
+		)
+	}
+
+	fingerprint3 := wrap.Fingerprint(wrap.Error(err, "failed to fetch user with ID 1"))
+	if fingerprint1 == fingerprint3 {
+		t.Error("expected errors with different message formats to have different fingerprints")
+	}
+
+	fingerprint4 := wrap.Fingerprint(
+		wrap.Errorf(errors.New("connection refused"), "failed to fetch user with ID %d", 1),
+	)
+	if fingerprint1 == fingerprint4 {
+		t.Error("expected errors with different causes to have different fingerprints")
+	}
+}
+
+func TestFingerprintNestedLists(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+
+	// The same errors, but nested differently
+	wrapped1 := wrap.Errors("outer", wrap.Errors("inner", err1), err2)
+	wrapped2 := wrap.Errors("outer", wrap.Errors("inner", err1, err2))
+
+	if wrap.Fingerprint(wrapped1) == wrap.Fingerprint(wrapped2) {
+		t.Error("expected errors with different tree structures to have different fingerprints")
+	}
+
+	if wrap.Fingerprint(nil) != "" {
+		t.Error("expected fingerprint of nil error to be empty")
+	}
+}
@@ -0,0 +1,42 @@
+// Package neterr implements the forwarding of [net.Error] methods shared by the wrap and ctxwrap
+// packages, for errors whose chain contains a net.Error.
+package neterr
+
+import (
+	"errors"
+	"net"
+)
+
+// Wraps returns true if one of the errors directly wrapped by the given error (returned by its
+// Unwrap method) satisfies net.Error. Errors from the wrap packages satisfy net.Error when their
+// chain contains one, so a new error only has to check the errors it wraps, instead of its whole
+// chain. Other errors that wrap a net.Error without satisfying it themselves (e.g. from
+// fmt.Errorf) are not looked through.
+func Wraps(err error) bool {
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		_, ok := err.Unwrap().(net.Error)
+		return ok
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range err.Unwrap() {
+			if _, ok := wrapped.(net.Error); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Timeout forwards to the Timeout method of the first net.Error in the chain of the given error.
+// The net.Error is looked up when called, rather than when the error is created.
+func Timeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Temporary forwards to the Temporary method of the first net.Error in the chain of the given
+// error.
+func Temporary(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Temporary()
+}
@@ -0,0 +1,80 @@
+package wrap
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Multi-errors with at least this many wrapped errors get an index for Is (see isIndex).
+const isIndexThreshold = 64
+
+// An index of the hashable errors (see isHashable) in the tree of a wide multi-error, so that
+// repeated errors.Is checks for errors deep in the tree (such as sentinels checked in error
+// handlers) don't traverse all the wrapped errors every time. The index is built on the first Is
+// call, since most errors are never checked.
+type isIndex struct {
+	once   sync.Once
+	errors map[error]struct{}
+}
+
+// Returns an index for a multi-error with the given wrapped errors, or nil if there are too few of
+// them for an index to pay off.
+func newIsIndex(wrapped []error) *isIndex {
+	if len(wrapped) < isIndexThreshold {
+		return nil
+	}
+	return &isIndex{}
+}
+
+// Returns true if the given target is equal to one of the given wrapped errors or an error they
+// wrap. The index must have been created for the same wrapped errors.
+func (index *isIndex) contains(wrapped []error, target error) bool {
+	if !isHashable(target) {
+		return false
+	}
+
+	index.once.Do(func() {
+		index.errors = make(map[error]struct{}, len(wrapped))
+		for _, wrappedErr := range wrapped {
+			forEachInChain(wrappedErr, func(err error) {
+				if isHashable(err) {
+					index.errors[err] = struct{}{}
+				}
+			})
+		}
+	})
+
+	_, found := index.errors[target]
+	return found
+}
+
+// Returns true if the given error can be used as a map key without panicking. A comparable type is
+// not enough, since a struct or interface may hold uncomparable values (e.g. a value marker
+// wrapping an error with a slice field), so only pointers and basic kinds are indexed. Errors
+// created by this package and errors.New are pointers, so sentinel errors are still indexed.
+func isHashable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch reflect.TypeOf(err).Kind() {
+	case reflect.Pointer, reflect.String, reflect.Bool, reflect.Uintptr,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
+
+// Is reports whether the target is equal to one of the wrapped errors or an error they wrap, using
+// an index for multi-errors with many wrapped errors, for [errors.Is]. Smaller multi-errors leave
+// the check to errors.Is, which traverses them through Unwrap.
+//
+// If the target is not found, errors.Is still traverses the wrapped errors, since they may match
+// the target through their own Is methods, or be unhashable errors equal to the target. The index
+// therefore only speeds up checks that find the target.
+func (err wrappedErrors) Is(target error) bool {
+	return err.isIndex != nil && err.isIndex.contains(err.wrapped, target)
+}
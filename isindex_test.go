@@ -0,0 +1,55 @@
+package wrap_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+func TestErrorsIsWithManyWrappedErrors(t *testing.T) {
+	sentinel := errors.New("not found")
+	errs := make([]error, 200)
+	for i := range errs {
+		errs[i] = fmt.Errorf("error %d", i)
+	}
+	errs[150] = wrap.Error(sentinel, "failed to fetch user")
+	// Non-comparable errors must not break the index
+	errs[10] = multiErr{errors.New("error")}
+	// Nor may comparable value markers holding non-comparable errors, which panic when hashed
+	errs[20] = wrap.MarkRemote(multiErr{errors.New("error")})
+	errs[30] = wrap.Cached(multiErr{errors.New("error")}, time.Now())
+
+	wrapped := wrap.Errors("batch failed", errs...)
+	for i := 0; i < 2; i++ {
+		if !errors.Is(wrapped, sentinel) {
+			t.Error("expected errors.Is to find error deep in multi-error")
+		}
+		if errors.Is(wrapped, errors.New("not found")) {
+			t.Error("expected errors.Is to return false for error not in multi-error")
+		}
+	}
+
+	other := errors.New("timeout")
+	appended := wrap.Append(wrapped, other)
+	if !errors.Is(appended, other) || !errors.Is(appended, sentinel) {
+		t.Error("expected errors.Is to find errors in appended multi-error")
+	}
+}
+
+func BenchmarkErrorsIs(b *testing.B) {
+	sentinel := errors.New("not found")
+	errs := make([]error, 500)
+	for i := range errs {
+		errs[i] = wrap.Error(fmt.Errorf("error %d", i), "wrapped error")
+	}
+	errs[len(errs)-1] = wrap.Error(sentinel, "wrapped error")
+	wrapped := wrap.Errors("batch failed", errs...)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		errors.Is(wrapped, sentinel)
+	}
+}
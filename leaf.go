@@ -0,0 +1,78 @@
+package wrap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NewError creates a new error with the given message, like [errors.New]. Use it to create root
+// errors when you want to use this package for all errors, instead of mixing in the errors package.
+// Unlike errors.New, the error records its caller and creation time when enabled (see
+// [SetRecordCallers] and [SetRecordCreationTimes]), and is passed to the hook set with
+// [SetWrapHook].
+//
+// To attach structured log attributes to the error, use [NewErrorWithAttrs]. For stack traces and
+// codes, use [New] with a nil wrapped error.
+func NewError(message string) error {
+	return runWrapHook(&leafError{message: message, caller: recordCaller()})
+}
+
+// NewErrorf creates a new error with a formatted message, like [fmt.Errorf] without a wrapped
+// error. It forwards the given message format and args to [fmt.Sprintf] to construct the message,
+// and works like [NewError] otherwise. Like [Errorf], errors embedded with %w are matched by
+// [errors.Is] and [errors.As], and [Fingerprint] uses the format string rather than the formatted
+// message, so that errors with different args are grouped together.
+func NewErrorf(messageFormat string, formatArgs ...any) error {
+	formatted := fmt.Errorf(messageFormat, formatArgs...)
+
+	err := leafError{
+		message:       formatted.Error(),
+		messageFormat: messageFormat,
+		caller:        recordCaller(),
+	}
+	switch formatted := formatted.(type) {
+	case interface{ Unwrap() error }:
+		err.embedded = []error{formatted.Unwrap()}
+	case interface{ Unwrap() []error }:
+		err.embedded = formatted.Unwrap()
+	}
+	return runWrapHook(&err)
+}
+
+type leafError struct {
+	message string
+	// The format string that the message was constructed from, if created with NewErrorf.
+	messageFormat string
+	// Errors embedded with %w in NewErrorf, checked by Is and As.
+	embedded []error
+	// Where the error was created, if enabled with SetRecordCallers or SetRecordCreationTimes.
+	caller *callSite
+}
+
+func (err *leafError) Error() string {
+	return err.message
+}
+
+// Is reports whether any of the errors embedded with %w match the target, for [errors.Is].
+func (err *leafError) Is(target error) bool {
+	for _, embedded := range err.embedded {
+		if errors.Is(embedded, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error embedded with %w that matches the target, for [errors.As].
+func (err *leafError) As(target any) bool {
+	for _, embedded := range err.embedded {
+		if errors.As(embedded, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (err *leafError) callSite() *callSite {
+	return err.caller
+}
@@ -0,0 +1,52 @@
+package wrap_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestNewError(t *testing.T) {
+	err := wrap.NewError("user not found")
+	wrapped := wrap.Error(err, "failed to fetch user")
+
+	expected := `failed to fetch user
+- user not found`
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to match the created error")
+	}
+	if errors.Is(err, wrap.NewError("user not found")) {
+		t.Error("expected separately created errors not to be equal")
+	}
+}
+
+func TestNewErrorf(t *testing.T) {
+	err := wrap.NewErrorf("user with ID %d not found: %w", 1, fs.ErrNotExist)
+	assertEqualErrorStrings(t, err, "user with ID 1 not found: file does not exist")
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("expected errors.Is to match error embedded with %w")
+	}
+	other := wrap.NewErrorf("user with ID %d not found: %w", 2, fs.ErrNotExist)
+	if wrap.Fingerprint(err) != wrap.Fingerprint(other) {
+		t.Error("expected errors with the same format string to have the same fingerprint")
+	}
+	if wrap.Fingerprint(err) == wrap.Fingerprint(wrap.NewError("user with ID 1 not found")) {
+		t.Error("expected errors with different messages to have different fingerprints")
+	}
+}
+
+func TestNewErrorRecordsCaller(t *testing.T) {
+	wrap.SetRecordCallers(true)
+	defer wrap.SetRecordCallers(false)
+
+	err := wrap.NewError("user not found")
+	trail := wrap.Trail(wrap.Error(err, "failed to fetch user"))
+	if len(trail) != 2 || trail[1].Message != "user not found" {
+		t.Errorf("expected trail entry for created error, got %+v", trail)
+	}
+}
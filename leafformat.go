@@ -0,0 +1,92 @@
+package wrap
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Formats a leaf error, returning false if the error is not of the formatter's type.
+type leafFormatter func(err error) (message string, attrs []slog.Attr, ok bool)
+
+// The registered leaf formatters. The list is copied on write, so that the formatter can read it
+// without taking a lock.
+var (
+	leafFormatters     atomic.Pointer[[]leafFormatter]
+	leafFormattersLock sync.Mutex // Serializes writers
+)
+
+// RegisterLeafFormatter registers a function for rendering leaf errors of type T (i.e. errors that
+// don't wrap other errors with a message), for error types whose default error strings are verbose
+// or unstructured (such as database driver errors). The returned message replaces the error string
+// in the formatted output of wrapped errors (and in [FormatDOT] and [Walk]), and the returned
+// attributes are included by [Attrs].
+//
+// Example:
+//
+//	wrap.RegisterLeafFormatter(func(err *pq.Error) (string, []slog.Attr) {
+//		return err.Message, []slog.Attr{slog.String("pg_code", string(err.Code))}
+//	})
+//
+// T is matched with a type assertion, so it may also be an interface type. If several formatters
+// match an error, the first registered one is used. It is safe to call concurrently, but is
+// typically called at program startup.
+func RegisterLeafFormatter[T error](format func(err T) (message string, attrs []slog.Attr)) {
+	formatter := func(err error) (string, []slog.Attr, bool) {
+		typedErr, ok := err.(T)
+		if !ok {
+			return "", nil, false
+		}
+		message, attrs := format(typedErr)
+		return message, attrs, true
+	}
+
+	leafFormattersLock.Lock()
+	defer leafFormattersLock.Unlock()
+
+	var newFormatters []leafFormatter
+	if oldFormatters := leafFormatters.Load(); oldFormatters != nil {
+		newFormatters = slices.Clone(*oldFormatters)
+	}
+	newFormatters = append(newFormatters, formatter)
+	leafFormatters.Store(&newFormatters)
+	attrsConfigVersion.Add(1)
+}
+
+// Returns the message and attributes from the first registered leaf formatter matching the given
+// error, or false if none match.
+func formatLeaf(err error) (message string, attrs []slog.Attr, ok bool) {
+	formatters := leafFormatters.Load()
+	if formatters == nil {
+		return "", nil, false
+	}
+
+	for _, formatter := range *formatters {
+		if message, attrs, ok := formatter(err); ok {
+			return message, attrs, true
+		}
+	}
+	return "", nil, false
+}
+
+// Returns the message of the given leaf error, from a registered leaf formatter if one matches, or
+// the error string otherwise.
+func leafMessage(err error) string {
+	if message, _, ok := formatLeaf(err); ok {
+		return message
+	}
+	return err.Error()
+}
+
+// Returns the attributes from the registered leaf formatter matching the given error, if the error
+// is displayed as a leaf by the formatter (i.e. it is not a wrapping error or a marker).
+func leafAttrs(err error) []slog.Attr {
+	switch err.(type) {
+	case wrappingError, wrappingErrors, markerError:
+		return nil
+	}
+
+	_, attrs, _ := formatLeaf(err)
+	return attrs
+}
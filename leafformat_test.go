@@ -0,0 +1,77 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+type driverError struct {
+	code   string
+	detail string
+}
+
+func (err *driverError) Error() string {
+	return "driver: " + err.code + ": " + err.detail + " (full verbose driver output...)"
+}
+
+func init() {
+	wrap.RegisterLeafFormatter(func(err *driverError) (string, []slog.Attr) {
+		return err.detail, []slog.Attr{slog.String("driver_code", err.code)}
+	})
+}
+
+func TestLeafFormatter(t *testing.T) {
+	err := &driverError{code: "23505", detail: "duplicate key value"}
+	wrapped := wrap.ErrorWithAttrs(err, "failed to insert user", "table", "users")
+
+	expected := `failed to insert user
+- duplicate key value`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrSlices(t, wrap.Attrs(wrapped), []slog.Attr{
+		slog.String("table", "users"),
+		slog.String("driver_code", "23505"),
+	})
+
+	layers := wrap.Flatten(wrapped)
+	if len(layers) != 2 || layers[1].Message != "duplicate key value" {
+		t.Errorf("expected leaf formatter to be used by Flatten, got %+v", layers)
+	}
+}
+
+func TestLeafFormatterOtherTypes(t *testing.T) {
+	_, parseErr := strconv.Atoi("abc")
+	wrapped := wrap.Error(parseErr, "invalid input")
+
+	expected := `invalid input
+- strconv.Atoi: parsing "abc": invalid syntax`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	if attrs := wrap.Attrs(wrap.Error(errors.New("error"), "wrapped")); len(attrs) != 0 {
+		t.Errorf("expected no attributes for errors without leaf formatter, got %v", attrs)
+	}
+}
+
+type lateFormattedError struct{}
+
+func (lateFormattedError) Error() string {
+	return "late formatted error"
+}
+
+func TestLeafFormatterRegisteredAfterAttrs(t *testing.T) {
+	wrapped := wrap.ErrorWithAttrs(lateFormattedError{}, "wrapped error", "key", "value")
+	assertEqualAttrSlices(t, wrap.Attrs(wrapped), []slog.Attr{slog.String("key", "value")})
+
+	// Registering a formatter must invalidate the attributes cached by the first call
+	wrap.RegisterLeafFormatter(func(err lateFormattedError) (string, []slog.Attr) {
+		return err.Error(), []slog.Attr{slog.Bool("late", true)}
+	})
+	assertEqualAttrSlices(t, wrap.Attrs(wrapped), []slog.Attr{
+		slog.String("key", "value"),
+		slog.Bool("late", true),
+	})
+}
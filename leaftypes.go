@@ -0,0 +1,49 @@
+package wrap
+
+import (
+	"fmt"
+)
+
+// LeafTypes returns the Go type names (as formatted by %T, e.g. "*net.OpError") of the leaf errors
+// in the given error chain, i.e. the errors that the formatter displays as leaves: the first error
+// in each branch that is not a wrapping layer or marker from this package. An external error that
+// wraps other errors is reported as itself (e.g. "*fs.PathError" for an error from [os.Open]),
+// since its concrete type is often the fastest way to triage the error. For multi-errors, the
+// leaves of each branch are included in order.
+func LeafTypes(err error) []string {
+	var types []string
+	forEachLeaf(err, func(leaf error) {
+		types = append(types, fmt.Sprintf("%T", leaf))
+	})
+	return types
+}
+
+// Calls the given function for each error that the formatter displays as a leaf in the given error
+// tree, i.e. the first error in each branch that is not a wrapping error or a marker.
+func forEachLeaf(err error, fn func(leaf error)) {
+	switch err := unwrapMarkers(err).(type) {
+	case nil:
+		return
+	case wrappingError:
+		forEachLeaf(err.Unwrap(), fn)
+	case wrappingErrors:
+		for _, wrappedErr := range err.Unwrap() {
+			forEachLeaf(wrappedErr, fn)
+		}
+	default:
+		fn(err)
+	}
+}
+
+// Returns true if the given error doesn't wrap any other errors (either because it has no Unwrap
+// method, or because its Unwrap method returns nil).
+func isLeaf(err error) bool {
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		return err.Unwrap() == nil
+	case interface{ Unwrap() []error }:
+		return len(err.Unwrap()) == 0
+	default:
+		return true
+	}
+}
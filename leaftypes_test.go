@@ -0,0 +1,46 @@
+package wrap_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"slices"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestLeafTypes(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "config.json", Err: fs.ErrNotExist}
+	err := wrap.Errors(
+		"failed to load configuration",
+		wrap.Error(pathErr, "failed to read config file"),
+		wrap.AddStack(errors.New("missing environment variable")),
+	)
+
+	types := wrap.LeafTypes(err)
+	// The *fs.PathError wraps fs.ErrNotExist, but it is not a wrap layer, so it is the leaf
+	expected := []string{"*fs.PathError", "*errors.errorString"}
+	if !slices.Equal(types, expected) {
+		t.Errorf("unexpected leaf types; got %v, want %v", types, expected)
+	}
+}
+
+func TestLeafTypesExternalWrappingError(t *testing.T) {
+	err := wrap.Error(&net.OpError{Op: "dial", Err: context.DeadlineExceeded}, "failed to connect")
+
+	types := wrap.LeafTypes(err)
+	if !slices.Equal(types, []string{"*net.OpError"}) {
+		t.Errorf("unexpected leaf types; got %v", types)
+	}
+}
+
+func TestLeafTypesSingleError(t *testing.T) {
+	err := wrap.Error(&fs.PathError{Op: "open", Path: "config.json"}, "failed to read config file")
+
+	types := wrap.LeafTypes(err)
+	if !slices.Equal(types, []string{"*fs.PathError"}) {
+		t.Errorf("unexpected leaf types; got %v", types)
+	}
+}
@@ -0,0 +1,81 @@
+package wrap
+
+import (
+	"log/slog"
+)
+
+// LevelCritical is a log level above [slog.LevelError], for errors that need immediate attention.
+// slog has no built-in level for this, so handlers print it as "ERROR+4".
+const LevelCritical = slog.Level(12)
+
+// ErrorWithLevel wraps the given error with a message for context, and attaches the given log
+// severity level to it. This lets the code that knows how severe an error is (e.g. that a failed
+// cache write is only a warning) decide the level it is logged with, instead of the handler at the
+// top of the stack. The level is not included in the error string, but is available through the
+// Level method of the returned error (for logging libraries that look for it) and [LevelOf].
+//
+// Example:
+//
+//	err := errors.New("connection refused")
+//	wrapped := wrap.ErrorWithLevel(err, slog.LevelWarn, "failed to write to cache")
+//	fmt.Println(wrapped)
+//	// failed to write to cache
+//	// - connection refused
+//
+// The returned error implements the Unwrap method from the standard errors package, so it works
+// with [errors.Is] and [errors.As].
+func ErrorWithLevel(wrapped error, level slog.Level, message string) error {
+	err := &levelError{
+		wrapped: &wrappedError{wrapped: wrapped, message: message, caller: recordCaller()},
+		level:   level,
+	}
+	return runWrapHook(withNetErrorFrom(err, wrapped))
+}
+
+// LevelOf returns the log level attached to the given error or the errors it wraps with
+// [ErrorWithLevel] (or the level of a [Sentinel] in the chain), if any. If there are several levels
+// in the chain, the outermost one is returned, since the outer layers know more about the impact of
+// the error. Since wrapping layers don't forward the Level method, use this instead of checking the
+// method when the error may have been wrapped.
+func LevelOf(err error) (level slog.Level, ok bool) {
+	forEachInChain(err, func(err error) {
+		if leveledErr, isLeveled := err.(leveledError); isLeveled && !ok {
+			level, ok = leveledErr.errorLevel()
+		}
+	})
+	return level, ok
+}
+
+// leveledError is implemented by errors that may carry a log level, for LevelOf.
+type leveledError interface {
+	errorLevel() (level slog.Level, ok bool)
+}
+
+// Attaches a log level to a wrapped error, as a marker around the layer it applies to.
+type levelError struct {
+	wrapped error
+	level   slog.Level
+}
+
+func (err *levelError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *levelError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *levelError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// Level returns the log level attached to the error, for logging libraries that look for this
+// method.
+func (err *levelError) Level() slog.Level {
+	return err.level
+}
+
+func (err *levelError) errorLevel() (level slog.Level, ok bool) {
+	return err.level, true
+}
@@ -0,0 +1,70 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestErrorWithLevel(t *testing.T) {
+	err := errors.New("connection refused")
+	inner := wrap.ErrorWithLevel(err, slog.LevelWarn, "failed to write to cache")
+	outer := wrap.Error(inner, "failed to update user")
+
+	expected := `failed to update user
+- failed to write to cache
+- connection refused`
+
+	assertEqualErrorStrings(t, outer, expected)
+
+	leveled, ok := inner.(interface{ Level() slog.Level })
+	if !ok || leveled.Level() != slog.LevelWarn {
+		t.Error("expected error to implement Level method returning the attached level")
+	}
+	if level, ok := wrap.LevelOf(outer); !ok || level != slog.LevelWarn {
+		t.Errorf("unexpected level; got (%v, %t)", level, ok)
+	}
+	if !errors.Is(outer, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+	if _, ok := wrap.LevelOf(errors.New("error")); ok {
+		t.Error("expected LevelOf to return false for error without level")
+	}
+}
+
+func TestLevelOfReturnsOutermost(t *testing.T) {
+	inner := wrap.ErrorWithLevel(errors.New("error"), slog.LevelWarn, "inner wrapped error")
+	outer := wrap.ErrorWithLevel(inner, wrap.LevelCritical, "outer wrapped error")
+
+	if level, _ := wrap.LevelOf(outer); level != wrap.LevelCritical {
+		t.Errorf("expected outermost level, got %v", level)
+	}
+}
+
+func TestBuilderLevel(t *testing.T) {
+	err := wrap.New(errors.New("error")).
+		Message("wrapped error").
+		Stack().
+		With(wrap.WithLevel(slog.LevelWarn)).
+		Err()
+
+	leveled, ok := err.(interface{ Level() slog.Level })
+	if !ok || leveled.Level() != slog.LevelWarn {
+		t.Error("expected built error to implement Level method returning the attached level")
+	}
+	assertEqualErrorStrings(t, err, "wrapped error\n- error")
+}
+
+func TestSentinelLevel(t *testing.T) {
+	errCacheMiss := wrap.NewSentinel("cache miss", wrap.WithLevel(slog.LevelDebug))
+	err := wrap.Error(errCacheMiss, "failed to get cached user")
+
+	if level, ok := wrap.LevelOf(err); !ok || level != slog.LevelDebug {
+		t.Errorf("unexpected level; got (%v, %t)", level, ok)
+	}
+	if _, ok := wrap.LevelOf(wrap.NewSentinel("not found")); ok {
+		t.Error("expected LevelOf to return false for sentinel without level")
+	}
+}
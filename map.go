@@ -0,0 +1,142 @@
+package wrap
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// Map rebuilds the given error tree with the layers transformed by the given function, for changing
+// messages or attributes while keeping the structure of the tree (e.g. to localize or redact
+// messages at an API boundary). The function is called for each layer as visited by [Walk], and
+// the rebuilt layer gets the Message and Attrs of the returned layer. Changes to the other fields
+// of the layer are ignored.
+//
+// Example:
+//
+//	err := wrap.ErrorWithAttrs(loginErr, "login failed", "password", "hunter2")
+//	redacted := wrap.Map(err, func(layer wrap.Layer) wrap.Layer {
+//		for i, attr := range layer.Attrs {
+//			if attr.Key == "password" {
+//				layer.Attrs[i].Value = slog.StringValue("REDACTED")
+//			}
+//		}
+//		return layer
+//	})
+//
+// The rebuilt errors match the errors of the original tree with [errors.Is] and [errors.As], but
+// only the messages and attributes of the layers are kept in the rebuilt tree. Other metadata (such
+// as codes, stack traces and contexts) is only kept in the form of attributes. If the given error
+// is nil, Map returns nil.
+func Map(err error, fn func(layer Layer) Layer) error {
+	return withNetErrorFrom(mapLayer(err, 0, fn), err)
+}
+
+func mapLayer(err error, depth int, fn func(layer Layer) Layer) error {
+	if err == nil {
+		return nil
+	}
+
+	layer := newLayer(err, depth)
+	mapped := fn(layer)
+
+	switch layer.Err.(type) {
+	case wrappingErrors:
+		children := make([]error, len(layer.Children))
+		for i, child := range layer.Children {
+			children[i] = mapLayer(child, depth+1, fn)
+		}
+		return &mappedErrors{
+			wrappedErrors: wrappedErrors{message: mapped.Message, wrapped: children},
+			attrs:         mapped.Attrs,
+			original:      err,
+		}
+	case wrappingError:
+		if len(layer.Children) == 1 {
+			return &mappedError{
+				wrappedError: wrappedError{
+					message: mapped.Message,
+					wrapped: mapLayer(layer.Children[0], depth+1, fn),
+				},
+				attrs:    mapped.Attrs,
+				original: err,
+			}
+		}
+	}
+
+	return &mappedLeafError{message: mapped.Message, attrs: mapped.Attrs, original: err}
+}
+
+// A wrapping layer rebuilt by Map.
+type mappedError struct {
+	wrappedError
+	attrs []slog.Attr
+	// The error that the layer was rebuilt from, for matching with errors.Is and errors.As.
+	original error
+}
+
+// LogAttrs returns the structured log attributes of the rebuilt layer, for logging libraries that
+// look for this method (such as [hermannm.dev/devlog/log]).
+func (err *mappedError) LogAttrs() []slog.Attr {
+	return err.attrs
+}
+
+// Is checks the original error that the layer was rebuilt from, for [errors.Is].
+func (err *mappedError) Is(target error) bool {
+	return errors.Is(err.original, target)
+}
+
+// As checks the original error that the layer was rebuilt from, for [errors.As].
+func (err *mappedError) As(target any) bool {
+	return errors.As(err.original, target)
+}
+
+// A multi-error layer rebuilt by Map.
+type mappedErrors struct {
+	wrappedErrors
+	attrs    []slog.Attr
+	original error
+}
+
+// LogAttrs returns the structured log attributes of the rebuilt layer, for logging libraries that
+// look for this method (such as [hermannm.dev/devlog/log]).
+func (err *mappedErrors) LogAttrs() []slog.Attr {
+	return err.attrs
+}
+
+// Is checks the original error that the layer was rebuilt from, for [errors.Is].
+func (err *mappedErrors) Is(target error) bool {
+	return errors.Is(err.original, target)
+}
+
+// As checks the original error that the layer was rebuilt from, for [errors.As].
+func (err *mappedErrors) As(target any) bool {
+	return errors.As(err.original, target)
+}
+
+// A leaf layer rebuilt by Map. It doesn't unwrap to the original error, since that would bring
+// back the original message and attributes.
+type mappedLeafError struct {
+	message  string
+	attrs    []slog.Attr
+	original error
+}
+
+func (err *mappedLeafError) Error() string {
+	return err.message
+}
+
+// LogAttrs returns the structured log attributes of the rebuilt layer, for logging libraries that
+// look for this method (such as [hermannm.dev/devlog/log]).
+func (err *mappedLeafError) LogAttrs() []slog.Attr {
+	return err.attrs
+}
+
+// Is checks the original error that the layer was rebuilt from, for [errors.Is].
+func (err *mappedLeafError) Is(target error) bool {
+	return errors.Is(err.original, target)
+}
+
+// As checks the original error that the layer was rebuilt from, for [errors.As].
+func (err *mappedLeafError) As(target any) bool {
+	return errors.As(err.original, target)
+}
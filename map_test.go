@@ -0,0 +1,62 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestMap(t *testing.T) {
+	first := errors.New("invalid email")
+	second := errors.New("invalid password")
+	err := wrap.ErrorWithAttrs(
+		wrap.Errors("validation failed", first, wrap.Error(second, "failed to parse password")),
+		"login failed",
+		"password",
+		"hunter2",
+	)
+
+	mapped := wrap.Map(err, func(layer wrap.Layer) wrap.Layer {
+		layer.Message = strings.ToUpper(layer.Message)
+		for i, attr := range layer.Attrs {
+			if attr.Key == "password" {
+				layer.Attrs[i].Value = slog.StringValue("REDACTED")
+			}
+		}
+		return layer
+	})
+
+	assertEqualErrorStrings(
+		t,
+		mapped,
+		`LOGIN FAILED
+- VALIDATION FAILED
+  - INVALID EMAIL
+  - FAILED TO PARSE PASSWORD
+    - INVALID PASSWORD`,
+	)
+	assertEqualAttrSlices(t, wrap.Attrs(mapped), []slog.Attr{slog.String("password", "REDACTED")})
+	assertEqualAttrSlices(t, wrap.Attrs(err), []slog.Attr{slog.String("password", "hunter2")})
+
+	if !errors.Is(mapped, first) || !errors.Is(mapped, second) {
+		t.Error("expected mapped error to match the errors of the original tree")
+	}
+
+	var multiErr interface{ Unwrap() []error }
+	if !errors.As(errors.Unwrap(mapped), &multiErr) || len(multiErr.Unwrap()) != 2 {
+		t.Error("expected mapped error to keep the structure of the original tree")
+	}
+}
+
+func TestMapNil(t *testing.T) {
+	mapped := wrap.Map(nil, func(layer wrap.Layer) wrap.Layer {
+		t.Error("expected map function not to be called for nil error")
+		return layer
+	})
+	if mapped != nil {
+		t.Errorf("expected nil error, got %v", mapped)
+	}
+}
@@ -0,0 +1,75 @@
+package wrap
+
+import (
+	"strings"
+)
+
+// Messages returns the messages of the layers of the given error, outermost first, for compact
+// error summaries (e.g. in API responses) or metric labels. Errors that only attach metadata (such
+// as stack traces or codes) are skipped, and the wrapped errors of multi-errors are included in
+// order (depth-first).
+//
+// Errors from other packages whose error string ends with ": " followed by the error string of the
+// error they wrap (as with [fmt.Errorf] and "prefix: %w") are split into the prefix and the
+// messages of the wrapped error:
+//
+//	err := fmt.Errorf("query failed: %w", errors.New("connection refused"))
+//	wrapped := wrap.Error(err, "failed to fetch user")
+//	fmt.Println(wrap.Messages(wrapped))
+//	// [failed to fetch user query failed connection refused]
+//
+// This differs from the formatter, which splits long external error strings at every ": " based on
+// their length, without looking at the wrapped errors. Errors that embed the wrapped error anywhere
+// else than at the end (e.g. fmt.Errorf("%w: query failed", err)) are not split, and their whole
+// error string is returned as one message. If the given error is nil, Messages returns nil.
+func Messages(err error) []string {
+	return appendMessages(nil, err)
+}
+
+func appendMessages(messages []string, err error) []string {
+	for err != nil {
+		err = unwrapMarkers(err)
+
+		switch wrapping := err.(type) {
+		case wrappingError:
+			messages = append(messages, wrapping.WrappingMessage())
+			err = wrapping.Unwrap()
+		case wrappingErrors:
+			messages = append(messages, wrapping.WrappingMessage())
+			for _, wrappedErr := range wrapping.Unwrap() {
+				messages = appendMessages(messages, wrappedErr)
+			}
+			return messages
+		default:
+			if message, _, ok := formatLeaf(err); ok {
+				return append(messages, message)
+			}
+			if prefix, wrapped, ok := splitErrorPrefix(err); ok {
+				messages = append(messages, prefix)
+				err = wrapped
+				continue
+			}
+			return append(messages, err.Error())
+		}
+	}
+	return messages
+}
+
+// Splits an error created with fmt.Errorf("prefix: %w", wrapped) (or an equivalent error type) into
+// its prefix and wrapped error. Returns false if the error string is not on that form.
+func splitErrorPrefix(err error) (prefix string, wrapped error, ok bool) {
+	unwrappable, isWrapping := err.(interface{ Unwrap() error })
+	if !isWrapping {
+		return "", nil, false
+	}
+	wrapped = unwrappable.Unwrap()
+	if wrapped == nil {
+		return "", nil, false
+	}
+
+	prefix, ok = strings.CutSuffix(err.Error(), ": "+wrapped.Error())
+	if !ok || prefix == "" {
+		return "", nil, false
+	}
+	return prefix, wrapped, true
+}
@@ -0,0 +1,37 @@
+package wrap_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestMessages(t *testing.T) {
+	err := fmt.Errorf("query failed: %w", errors.New("connection refused"))
+	inner := wrap.ErrorWithCode(err, "DB_UNAVAILABLE", "database lookup failed")
+	outer := wrap.Errors(
+		"failed to fetch users",
+		wrap.AddStack(inner),
+		fmt.Errorf("cache miss, no prefix split %w", errors.New("not found")),
+		fmt.Errorf("%w: retries exhausted", errors.New("timeout")),
+	)
+
+	expected := []string{
+		"failed to fetch users",
+		"database lookup failed",
+		"query failed",
+		"connection refused",
+		"cache miss, no prefix split not found",
+		"timeout: retries exhausted",
+	}
+
+	if messages := wrap.Messages(outer); !slices.Equal(messages, expected) {
+		t.Errorf("unexpected messages\nwant: %q\n got: %q", expected, messages)
+	}
+	if messages := wrap.Messages(nil); messages != nil {
+		t.Errorf("expected nil messages for nil error, got %q", messages)
+	}
+}
@@ -0,0 +1,50 @@
+package wrap
+
+// FromMultiError wraps the errors combined in the given multi-error with a message, so that they
+// are displayed as a list (like [Errors]) instead of one long line. This is for multi-errors from
+// other libraries, such as go.uber.org/multierr (which joins errors with "; ") and [errors.Join]
+// (which joins them with newlines):
+//
+//	err := multierr.Combine(errors.New("username too long"), errors.New("invalid email"))
+//	wrapped := wrap.FromMultiError(err, "user creation failed")
+//	fmt.Println(wrapped)
+//	// user creation failed
+//	// - username too long
+//	// - invalid email
+//
+// Multi-errors are recognized by an Errors() []error method (used by go.uber.org/multierr) or an
+// Unwrap() []error method (used by the standard library). Other errors are wrapped with the message
+// like in [Error]. If the given error is nil, FromMultiError returns nil.
+//
+// For the other direction, multi-errors created by this package implement the same Errors method,
+// so go.uber.org/multierr.Errors returns their wrapped errors.
+func FromMultiError(err error, message string) error {
+	switch multiErr := err.(type) {
+	case nil:
+		return nil
+	case interface{ Errors() []error }:
+		wrapped := multiErr.Errors()
+		return runWrapHook(&wrappedErrors{
+			message: message,
+			wrapped: wrapped,
+			caller:  recordCaller(),
+			isIndex: newIsIndex(wrapped),
+		})
+	case interface{ Unwrap() []error }:
+		wrapped := multiErr.Unwrap()
+		return runWrapHook(&wrappedErrors{
+			message: message,
+			wrapped: wrapped,
+			caller:  recordCaller(),
+			isIndex: newIsIndex(wrapped),
+		})
+	default:
+		return runWrapHook(&wrappedError{wrapped: err, message: message, caller: recordCaller()})
+	}
+}
+
+// Errors returns the wrapped errors, for libraries that combine errors with this method (such as
+// go.uber.org/multierr).
+func (err wrappedErrors) Errors() []error {
+	return err.wrapped
+}
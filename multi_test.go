@@ -0,0 +1,61 @@
+package wrap_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+// Mimics the error type of go.uber.org/multierr, which joins errors with "; " and exposes them with
+// an Errors method.
+type multiErr []error
+
+func (errs multiErr) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs multiErr) Errors() []error {
+	return errs
+}
+
+func TestFromMultiError(t *testing.T) {
+	err1 := errors.New("username too long")
+	err2 := errors.New("invalid email")
+
+	expected := `user creation failed
+- username too long
+- invalid email`
+
+	for _, multi := range []error{multiErr{err1, err2}, errors.Join(err1, err2)} {
+		wrapped := wrap.FromMultiError(multi, "user creation failed")
+		assertEqualErrorStrings(t, wrapped, expected)
+		if !errors.Is(wrapped, err2) {
+			t.Error("expected errors.Is to return true for combined error")
+		}
+	}
+
+	wrapped := wrap.FromMultiError(err1, "user creation failed")
+	assertEqualErrorStrings(t, wrapped, "user creation failed\n- username too long")
+
+	if wrap.FromMultiError(nil, "user creation failed") != nil {
+		t.Error("expected FromMultiError to return nil for nil error")
+	}
+}
+
+func TestErrorsMethod(t *testing.T) {
+	err1 := errors.New("username too long")
+	err2 := errors.New("invalid email")
+	wrapped := wrap.Errors("user creation failed", err1, err2)
+
+	combined, ok := wrapped.(interface{ Errors() []error })
+	if !ok || !slices.Equal(combined.Errors(), []error{err1, err2}) {
+		t.Error("expected multi-error to expose wrapped errors through Errors method")
+	}
+}
@@ -0,0 +1,38 @@
+package wrap
+
+// Must returns the captured value if the captured error is nil, and otherwise panics with the error
+// wrapped with the given message (like [Error]) and a stack trace of the caller (see [AddStack]).
+// It is meant for initialization code and tests, where an error is unrecoverable, but you still
+// want to know what failed:
+//
+//	var config = wrap.Value(loadConfig("config.json")).Must("failed to load config")
+//
+// Use [Must0] for functions that only return an error.
+func (result ValueResult[T]) Must(message string) T {
+	if result.err != nil {
+		panic(&stackError{wrapped: Error(result.err, message), stack: CaptureStack(1)})
+	}
+	return result.value
+}
+
+// Must returns the captured values if the captured error is nil, and otherwise panics with the
+// error wrapped with the given message and a stack trace of the caller (like [ValueResult.Must]).
+func (result Value2Result[T1, T2]) Must(message string) (T1, T2) {
+	if result.err != nil {
+		panic(&stackError{wrapped: Error(result.err, message), stack: CaptureStack(1)})
+	}
+	return result.value1, result.value2
+}
+
+// Must0 panics with the given error wrapped with the given message (like [Error]) and a stack trace
+// of the caller (see [AddStack]), if the error is non-nil. It is meant for initialization code and
+// tests, like [ValueResult.Must], but for functions that only return an error.
+//
+// Example:
+//
+//	wrap.Must0(db.Ping(), "failed to connect to database")
+func Must0(err error, message string) {
+	if err != nil {
+		panic(&stackError{wrapped: Error(err, message), stack: CaptureStack(1)})
+	}
+}
@@ -0,0 +1,56 @@
+package wrap_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestMust(t *testing.T) {
+	if value := wrap.Value(strconv.Atoi("123")).Must("failed to parse"); value != 123 {
+		t.Errorf("unexpected value %d", value)
+	}
+
+	err := recoverError(t, func() { wrap.Value(strconv.Atoi("abc")).Must("failed to parse") })
+
+	expected := `failed to parse
+- strconv.Atoi: parsing "abc": invalid syntax`
+
+	assertEqualErrorStrings(t, err, expected)
+
+	stack, ok := wrap.Stack(err)
+	if !ok {
+		t.Fatal("expected panic error to have a stack trace")
+	}
+	if !strings.HasPrefix(stack.Frames()[0].Function, "hermannm.dev/wrap_test.TestMust") {
+		t.Errorf("expected stack trace to start at the caller, got %s", stack.Frames()[0].Function)
+	}
+}
+
+func TestMust0(t *testing.T) {
+	wrap.Must0(nil, "failed to connect")
+
+	err := recoverError(t, func() { wrap.Must0(errors.New("timeout"), "failed to connect") })
+
+	expected := `failed to connect
+- timeout`
+
+	assertEqualErrorStrings(t, err, expected)
+}
+
+func recoverError(t *testing.T, fn func()) (err error) {
+	t.Helper()
+
+	defer func() {
+		var ok bool
+		if err, ok = recover().(error); !ok {
+			t.Fatal("expected function to panic with an error")
+		}
+	}()
+
+	fn()
+	return nil
+}
@@ -0,0 +1,59 @@
+package wrap
+
+import (
+	"net"
+
+	"hermannm.dev/wrap/internal/neterr"
+)
+
+// Added around errors created by this package when their chain contains a net.Error, so that they
+// still satisfy net.Error for code that type-asserts to it instead of using errors.As. The marker
+// is only added when there is a net.Error to forward to, so that other errors don't pass such
+// checks. Like other markers, the formatter skips it.
+type netMarkerError struct {
+	wrapped error
+	mergedAttrsCache
+}
+
+// Adds a netMarkerError around the given newly created error if it directly wraps a net.Error (see
+// neterr.Wraps), and the error does not already satisfy net.Error itself.
+func withNetError(err error) error {
+	if _, isNetErr := err.(net.Error); isNetErr || !neterr.Wraps(err) {
+		return err
+	}
+	return &netMarkerError{wrapped: err}
+}
+
+// Adds a netMarkerError around the given newly created error if the given error that it wraps
+// satisfies net.Error. Used instead of withNetError by constructors that add markers around a new
+// layer in the same call (such as Retryable), where the wrapped error is not directly wrapped by
+// the returned error.
+func withNetErrorFrom(err error, wrapped error) error {
+	if _, isNetErr := wrapped.(net.Error); !isNetErr || err == nil {
+		return err
+	}
+	return &netMarkerError{wrapped: err}
+}
+
+func (err *netMarkerError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *netMarkerError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *netMarkerError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// Timeout forwards to the Timeout method of the first [net.Error] in the chain.
+func (err *netMarkerError) Timeout() bool {
+	return neterr.Timeout(err.wrapped)
+}
+
+// Temporary forwards to the Temporary method of the first [net.Error] in the chain.
+func (err *netMarkerError) Temporary() bool {
+	return neterr.Temporary(err.wrapped)
+}
@@ -0,0 +1,31 @@
+package wrap
+
+import (
+	"time"
+)
+
+// Op runs the given function as a named operation. If the function fails, its error is wrapped
+// with the message "<op> failed", and the log attributes "op" (the operation name) and "elapsed"
+// (the time taken before the failure). This standardizes the convention of naming the operation
+// that failed, with minimal syntax at the call site.
+//
+// Example:
+//
+//	user, err := wrap.Op("fetch user", func() (User, error) {
+//		return db.GetUser(ctx, userID)
+//	})
+//	fmt.Println(err)
+//	// fetch user failed
+//	// - connection refused
+//
+// The function's result is returned as-is, also when it fails.
+func Op[T any](op string, fn func() (T, error)) (T, error) {
+	start := time.Now()
+
+	result, err := fn()
+	if err != nil {
+		return result, ErrorWithAttrs(err, op+" failed", "op", op, "elapsed", time.Since(start))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestOp(t *testing.T) {
+	err := errors.New("connection refused")
+	result, wrapped := wrap.Op("fetch user", func() (int, error) {
+		return 0, err
+	})
+
+	expected := `fetch user failed
+- connection refused`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if result != 0 {
+		t.Errorf("unexpected result; got %d, want %d", result, 0)
+	}
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to return true for operation error")
+	}
+
+	attrs := wrap.Attrs(wrapped)
+	if len(attrs) != 2 {
+		t.Fatalf("unexpected attributes: %v", attrs)
+	}
+	if !attrs[0].Equal(slog.String("op", "fetch user")) {
+		t.Errorf("unexpected op attribute: %v", attrs[0])
+	}
+	if attrs[1].Key != "elapsed" || attrs[1].Value.Kind() != slog.KindDuration {
+		t.Errorf("unexpected elapsed attribute: %v", attrs[1])
+	}
+}
+
+func TestOpSuccess(t *testing.T) {
+	result, err := wrap.Op("fetch user", func() (string, error) {
+		return "hermannm", nil
+	})
+
+	if err != nil {
+		t.Errorf("expected no error from successful operation, got %v", err)
+	}
+	if result != "hermannm" {
+		t.Errorf("unexpected result; got %s, want %s", result, "hermannm")
+	}
+}
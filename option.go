@@ -0,0 +1,156 @@
+package wrap
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Option sets a facet of an error built with [Builder]. Options can be applied with [Builder.With],
+// or grouped into named presets with [RegisterPreset].
+type Option func(builder *Builder)
+
+// E wraps the given error with a message for context, and applies the given options to it. It is a
+// single entry point for any combination of facets, so that new facets are added as options
+// instead of as new variants of the wrapping functions:
+//
+//	err := wrap.E(
+//		err,
+//		"failed to insert user",
+//		wrap.WithAttrs("table", "users"),
+//		wrap.WithCode("DB_CONFLICT"),
+//		wrap.WithStack(),
+//		wrap.WithContext(ctx),
+//	)
+//
+// Without options, the error is displayed in the same format as [Error]. Functions such as
+// [ErrorWithAttrs] and [ErrorWithCode] remain as shorthands for common combinations. If the given
+// error is nil, E creates a new root error with the message instead (like [Builder.Err]). Stack
+// traces added with [WithStack] start at the caller of E.
+func E(wrapped error, message string, options ...Option) error {
+	builder := Builder{wrapped: wrapped, message: message}
+	builder.With(options...)
+	if builder.stack == nil && builder.shouldCaptureStack() {
+		builder.stack = CaptureStack(1)
+	}
+	return builder.Err()
+}
+
+// WithAttrs returns an option that adds structured log attributes to the error (like
+// [Builder.Attrs]).
+func WithAttrs(attrs ...any) Option {
+	return func(builder *Builder) {
+		builder.Attrs(attrs...)
+	}
+}
+
+// WithPrivateAttrs returns an option that marks the attributes of the error as private to its layer
+// (like [Builder.PrivateAttrs]).
+func WithPrivateAttrs() Option {
+	return func(builder *Builder) {
+		builder.PrivateAttrs()
+	}
+}
+
+// WithCode returns an option that attaches a machine-readable error code to the error (like
+// [Builder.Code]).
+func WithCode(code string) Option {
+	return func(builder *Builder) {
+		builder.Code(code)
+	}
+}
+
+// WithLevel returns an option that attaches a log severity level to the error (like
+// [Builder.Level]).
+func WithLevel(level slog.Level) Option {
+	return func(builder *Builder) {
+		builder.Level(level)
+	}
+}
+
+// WithRetryable returns an option that marks the error as retryable (like [Builder.Retryable]).
+func WithRetryable() Option {
+	return func(builder *Builder) {
+		builder.Retryable()
+	}
+}
+
+// WithContext returns an option that attaches the given context to the error (like
+// [Builder.Context], which also describes how it differs from [hermannm.dev/wrap/ctxwrap]).
+func WithContext(ctx context.Context) Option {
+	return func(builder *Builder) {
+		builder.Context(ctx)
+	}
+}
+
+// WithErrorID returns an option that stamps the error with a unique ID (like [AddErrorID]).
+func WithErrorID() Option {
+	return func(builder *Builder) {
+		builder.errorID = true
+	}
+}
+
+// WithStack returns an option that attaches a stack trace to the error. Since options may be
+// created ahead of time (e.g. in a preset), the stack trace is captured when the error is created
+// by [Builder.Err], and starts at the caller of Err.
+func WithStack() Option {
+	return func(builder *Builder) {
+		builder.captureStack = true
+	}
+}
+
+// WithStackTrace returns an option that attaches the given stack trace to the error, for helpers
+// that capture the stack trace themselves (e.g. to skip their own frames with [CaptureStack]).
+func WithStackTrace(stack StackTrace) Option {
+	return func(builder *Builder) {
+		builder.stack = stack
+	}
+}
+
+// With applies the given options to the builder.
+func (builder *Builder) With(options ...Option) *Builder {
+	for _, option := range options {
+		option(builder)
+	}
+	return builder
+}
+
+var (
+	presets     = make(map[string][]Option)
+	presetsLock sync.RWMutex
+)
+
+// RegisterPreset registers a named group of options, which call sites can apply by name with
+// [Builder.Preset]. This lets you define error policies (such as "errors from external APIs get a
+// stack trace and a code") in one place, and change them without touching every call site:
+//
+//	func init() {
+//		wrap.RegisterPreset("external-api", wrap.WithStack(), wrap.WithCode("UPSTREAM_FAILURE"))
+//	}
+//
+//	func fetchInvoice() error {
+//		// ...
+//		return wrap.New(err).Message("failed to fetch invoice").Preset("external-api").Err()
+//	}
+//
+// Registering a preset with an existing name replaces it. It is safe to call concurrently, but is
+// typically called at program startup.
+func RegisterPreset(name string, options ...Option) {
+	presetsLock.Lock()
+	defer presetsLock.Unlock()
+
+	presets[name] = slices.Clone(options)
+}
+
+// Preset applies the options of the preset registered with the given name (see [RegisterPreset]).
+// If no preset is registered with the name, the builder is left unchanged, since failing to create
+// an error would hide the original error.
+func (builder *Builder) Preset(name string) *Builder {
+	presetsLock.RLock()
+	options := presets[name]
+	presetsLock.RUnlock()
+
+	// Options are applied without holding the lock, so they may register presets themselves
+	return builder.With(options...)
+}
@@ -0,0 +1,110 @@
+package wrap_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestPreset(t *testing.T) {
+	wrap.RegisterPreset(
+		"external-api",
+		wrap.WithStack(),
+		wrap.WithCode("UPSTREAM_FAILURE"),
+		wrap.WithAttrs("dependency", "billing"),
+	)
+
+	err := wrap.New(errors.New("connection reset")).
+		Message("failed to fetch invoice").
+		Preset("external-api").
+		Err()
+
+	expected := `failed to fetch invoice
+- connection reset`
+
+	assertEqualErrorStrings(t, err, expected)
+	assertEqualAttrSlices(t, wrap.Attrs(err), []slog.Attr{
+		slog.String("code", "UPSTREAM_FAILURE"),
+		slog.String("dependency", "billing"),
+	})
+
+	stack, ok := wrap.Stack(err)
+	if !ok {
+		t.Fatal("expected preset to attach a stack trace")
+	}
+	if frames := stack.Frames(); !strings.HasSuffix(frames[0].Function, "TestPreset") {
+		t.Errorf("expected stack trace to start at the caller of Err, got %s", frames[0].Function)
+	}
+}
+
+func TestUnknownPreset(t *testing.T) {
+	err := wrap.New(errors.New("error")).Message("wrapped error").Preset("unknown").Err()
+
+	assertEqualErrorStrings(t, err, `wrapped error
+- error`)
+	if _, ok := wrap.Stack(err); ok {
+		t.Error("expected unknown preset not to attach a stack trace")
+	}
+}
+
+func TestWith(t *testing.T) {
+	err := wrap.New(errors.New("error")).With(wrap.WithCode("CODE")).Err()
+
+	if code, _ := wrap.CodeOf(err); code != "CODE" {
+		t.Errorf("unexpected code %q", code)
+	}
+}
+
+func TestE(t *testing.T) {
+	type contextKey struct{}
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+
+	err := errors.New("duplicate key")
+	wrapped := wrap.E(
+		err,
+		"failed to insert user",
+		wrap.WithAttrs("table", "users"),
+		wrap.WithCode("DB_CONFLICT"),
+		wrap.WithStack(),
+		wrap.WithContext(ctx),
+	)
+
+	expected := `failed to insert user
+- duplicate key`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrSlices(
+		t,
+		wrap.Attrs(wrapped),
+		[]slog.Attr{slog.String("code", "DB_CONFLICT"), slog.String("table", "users")},
+	)
+
+	stack, ok := wrap.Stack(wrapped)
+	if !ok {
+		t.Fatal("expected error to have a stack trace")
+	}
+	if frames := stack.Frames(); !strings.HasSuffix(frames[0].Function, "TestE") {
+		t.Errorf("expected stack trace to start at the caller of E, got %s", frames[0].Function)
+	}
+
+	var withContext interface{ Context() context.Context }
+	if !errors.As(wrapped, &withContext) || withContext.Context().Value(contextKey{}) != "value" {
+		t.Error("expected error to have the given context")
+	}
+	if layers := wrap.Flatten(wrapped); layers[0].Context != ctx {
+		t.Error("expected context to be merged into the layer of the error")
+	}
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+}
+
+func TestEWithoutOptions(t *testing.T) {
+	err := errors.New("error")
+	expected := wrap.Error(err, "wrapped error").Error()
+	assertEqualErrorStrings(t, wrap.E(err, "wrapped error"), expected)
+}
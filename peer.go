@@ -0,0 +1,49 @@
+package wrap
+
+// Peer identifies the remote side of a call, for attributing dependency failures in logs. It is
+// meant to be filled in once per client, and attached to every failed call with [PeerError]. For
+// HTTP clients, [hermannm.dev/wrap/wraphttp.Transport] does this automatically.
+type Peer struct {
+	// Service is the name of the target service, e.g. "billing".
+	Service string
+	// Method is the called method or route, e.g. "billing.v1.Invoices/Create" or "POST /invoices".
+	Method string
+	// Host is the address of the target host, e.g. "billing.internal:8080".
+	Host string
+}
+
+// Log attribute keys used by PeerError.
+const (
+	PeerServiceKey = "peer_service"
+	PeerMethodKey  = "peer_method"
+	PeerHostKey    = "peer_host"
+)
+
+// PeerError wraps the given error from a call to the given peer with a message for context, and
+// attaches the peer's non-empty fields as log attributes (with the keys [PeerServiceKey],
+// [PeerMethodKey] and [PeerHostKey]). Clients can leave out fields they don't want to log.
+//
+// Example:
+//
+//	peer := wrap.Peer{Service: "billing", Method: "POST /invoices", Host: "billing.internal"}
+//	err := wrap.PeerError(errors.New("connection reset"), peer, "failed to create invoice")
+//	fmt.Println(err)
+//	// failed to create invoice
+//	// - connection reset
+func PeerError(wrapped error, peer Peer, message string) error {
+	return ErrorWithAttrs(wrapped, message, peer.attrs()...)
+}
+
+func (peer Peer) attrs() []any {
+	var attrs []any
+	if peer.Service != "" {
+		attrs = append(attrs, PeerServiceKey, peer.Service)
+	}
+	if peer.Method != "" {
+		attrs = append(attrs, PeerMethodKey, peer.Method)
+	}
+	if peer.Host != "" {
+		attrs = append(attrs, PeerHostKey, peer.Host)
+	}
+	return attrs
+}
@@ -0,0 +1,31 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestPeerError(t *testing.T) {
+	peer := wrap.Peer{Service: "billing", Method: "POST /invoices", Host: "billing.internal"}
+	err := wrap.PeerError(errors.New("connection reset"), peer, "failed to create invoice")
+
+	expected := `failed to create invoice
+- connection reset`
+
+	assertEqualErrorStrings(t, err, expected)
+	assertEqualAttrs(t, err, []slog.Attr{
+		slog.String(wrap.PeerServiceKey, "billing"),
+		slog.String(wrap.PeerMethodKey, "POST /invoices"),
+		slog.String(wrap.PeerHostKey, "billing.internal"),
+	})
+}
+
+func TestPeerErrorOmitsEmptyFields(t *testing.T) {
+	peer := wrap.Peer{Service: "billing"}
+	err := wrap.PeerError(errors.New("connection reset"), peer, "failed to create invoice")
+
+	assertEqualAttrs(t, err, []slog.Attr{slog.String(wrap.PeerServiceKey, "billing")})
+}
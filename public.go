@@ -0,0 +1,78 @@
+package wrap
+
+import (
+	"errors"
+)
+
+// ErrorWithPublicMessage wraps the given error with an internal message for context (like
+// [Error]), and attaches a public message that is safe to show to clients (such as in an HTTP or
+// gRPC response). The error string and logs keep the internal message and all the wrapped details,
+// while [PublicMessage] returns only the public message.
+//
+// Example:
+//
+//	err := errors.New("duplicate key value violates unique constraint \"users_email_key\"")
+//	wrapped := wrap.ErrorWithPublicMessage(
+//		err,
+//		"failed to insert user into database",
+//		"A user with this email already exists",
+//	)
+//	fmt.Println(wrapped)
+//	// failed to insert user into database
+//	// - duplicate key value violates unique constraint "users_email_key"
+//	message, _ := wrap.PublicMessage(wrapped)
+//	fmt.Println(message)
+//	// A user with this email already exists
+//
+// The returned error implements the Unwrap method from the standard errors package, so it works
+// with [errors.Is] and [errors.As].
+func ErrorWithPublicMessage(wrapped error, internalMessage string, publicMessage string) error {
+	return runWrapHook(&publicMessageError{
+		wrappedError: wrappedError{
+			wrapped: wrapped,
+			message: internalMessage,
+			caller:  recordCaller(),
+		},
+		public: publicMessage,
+	})
+}
+
+// PublicMessage returns the public message attached to the given error or the errors it wraps with
+// [ErrorWithPublicMessage], if any. If there are several public messages in the chain, the
+// outermost one is returned, since the outer layers know more about what the client was trying to
+// do. If there is none, handlers should fall back to a generic message (such as "Internal server
+// error") rather than the error string, which may leak internal details.
+func PublicMessage(err error) (message string, ok bool) {
+	var publicErr *publicMessageError
+	if errors.As(err, &publicErr) {
+		return publicErr.public, true
+	}
+	return "", false
+}
+
+// PublicMessages returns the public messages attached to the given error and the errors it wraps
+// with [ErrorWithPublicMessage], outermost first. This mirrors the internal chain of the error with
+// only the parts that are safe to show to clients, so that UIs can show the first message as a
+// headline (the same one as returned by [PublicMessage]), and the rest as expandable details:
+//
+//	err := wrap.ErrorWithPublicMessage(dbErr, "insert failed", "The email is already in use")
+//	err = wrap.ErrorWithPublicMessage(err, "signup failed", "Could not create your account")
+//	fmt.Println(wrap.PublicMessages(err))
+//	// [Could not create your account The email is already in use]
+//
+// The wrapped errors of multi-errors are included in order (depth-first). If there are no public
+// messages in the chain, PublicMessages returns nil.
+func PublicMessages(err error) []string {
+	var messages []string
+	forEachInChain(err, func(err error) {
+		if publicErr, ok := err.(*publicMessageError); ok {
+			messages = append(messages, publicErr.public)
+		}
+	})
+	return messages
+}
+
+type publicMessageError struct {
+	wrappedError
+	public string
+}
@@ -0,0 +1,64 @@
+package wrap_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestErrorWithPublicMessage(t *testing.T) {
+	err := errors.New(`duplicate key value violates unique constraint "users_email_key"`)
+	inner := wrap.ErrorWithPublicMessage(
+		err,
+		"failed to insert user into database",
+		"A user with this email already exists",
+	)
+	outer := wrap.Error(inner, "failed to register user")
+
+	expected := `failed to register user
+- failed to insert user into database
+- duplicate key value violates unique constraint "users_email_key"`
+
+	assertEqualErrorStrings(t, outer, expected)
+
+	message, ok := wrap.PublicMessage(outer)
+	if !ok || message != "A user with this email already exists" {
+		t.Errorf("unexpected public message; got (%q, %t)", message, ok)
+	}
+	if !errors.Is(outer, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+}
+
+func TestPublicMessageReturnsOutermost(t *testing.T) {
+	inner := wrap.ErrorWithPublicMessage(errors.New("error"), "inner", "Inner public message")
+	outer := wrap.ErrorWithPublicMessage(inner, "outer", "Outer public message")
+
+	if message, _ := wrap.PublicMessage(outer); message != "Outer public message" {
+		t.Errorf("expected outermost public message, got %q", message)
+	}
+	if _, ok := wrap.PublicMessage(errors.New("error")); ok {
+		t.Error("expected PublicMessage to return false for error without public message")
+	}
+}
+
+func TestPublicMessages(t *testing.T) {
+	inner := wrap.ErrorWithPublicMessage(
+		errors.New("duplicate key"),
+		"insert failed",
+		"The email is already in use",
+	)
+	middle := wrap.Error(inner, "failed to save user")
+	outer := wrap.ErrorWithPublicMessage(middle, "signup failed", "Could not create your account")
+
+	messages := wrap.PublicMessages(outer)
+	expected := []string{"Could not create your account", "The email is already in use"}
+	if !slices.Equal(messages, expected) {
+		t.Errorf("unexpected public messages\nwant: %q\n got: %q", expected, messages)
+	}
+	if messages := wrap.PublicMessages(errors.New("error")); messages != nil {
+		t.Errorf("expected nil for error without public messages, got %q", messages)
+	}
+}
@@ -0,0 +1,57 @@
+package wrap
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// RateLimited marks the given error as caused by rate limiting, recording the request limit that
+// was exceeded and the time at which the limit resets. Clients typically need the reset time to
+// know when to retry, so use [RateLimit] to retrieve it instead of parsing error messages. To pass
+// it on in an HTTP response, see [hermannm.dev/wrap/wraphttp.SetRateLimitHeaders].
+//
+// The marker does not change how the error is displayed. The returned error implements the Unwrap
+// method from the standard errors package, so it works with [errors.Is] and [errors.As].
+func RateLimited(wrapped error, limit int, resetAt time.Time) error {
+	return withNetError(&rateLimitedError{wrapped: wrapped, limit: limit, resetAt: resetAt})
+}
+
+// RateLimit returns the limit and reset time of the given error, if it or any error it wraps was
+// marked with [RateLimited]. If there are several rate limit markers in the chain, the outermost
+// one is used.
+func RateLimit(err error) (limit int, resetAt time.Time, ok bool) {
+	var rateLimited *rateLimitedError
+	if errors.As(err, &rateLimited) {
+		return rateLimited.limit, rateLimited.resetAt, true
+	}
+	return 0, time.Time{}, false
+}
+
+type rateLimitedError struct {
+	wrapped error
+	limit   int
+	resetAt time.Time
+}
+
+func (err *rateLimitedError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *rateLimitedError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *rateLimitedError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// LogAttrs returns structured log attributes for the rate limit marker, for logging libraries that
+// look for this method (such as [hermannm.dev/devlog/log]).
+func (err *rateLimitedError) LogAttrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("rate_limit", err.limit),
+		slog.Time("rate_limit_reset", err.resetAt),
+	}
+}
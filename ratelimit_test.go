@@ -0,0 +1,44 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+func TestRateLimited(t *testing.T) {
+	resetAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	err := errors.New("too many requests")
+	rateLimited := wrap.RateLimited(err, 100, resetAt)
+	wrapped := wrap.Error(rateLimited, "failed to fetch repositories")
+
+	expected := `failed to fetch repositories
+- too many requests`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	limit, actualResetAt, ok := wrap.RateLimit(wrapped)
+	if !ok {
+		t.Fatal("expected RateLimit to find rate limit marker")
+	}
+	if limit != 100 {
+		t.Errorf("unexpected rate limit; got %d, want %d", limit, 100)
+	}
+	if !actualResetAt.Equal(resetAt) {
+		t.Errorf("unexpected rate limit reset time; got %v, want %v", actualResetAt, resetAt)
+	}
+
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to return true for rate limited error")
+	}
+}
+
+func TestNotRateLimited(t *testing.T) {
+	wrapped := wrap.Error(errors.New("error"), "wrapped error")
+	if _, _, ok := wrap.RateLimit(wrapped); ok {
+		t.Error("expected RateLimit to return false for error without rate limit marker")
+	}
+}
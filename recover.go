@@ -0,0 +1,94 @@
+package wrap
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// PanicKey is the log attribute key that errors from recovered panics are marked with (see
+// [PanicError]).
+const PanicKey = "panic"
+
+// Recover recovers a panic, and converts it to a [PanicError] wrapped with the given message, which
+// it stores in the error pointed to by errPtr. It must be called directly in a defer statement with
+// a pointer to a named error return value, since Go only lets a deferred function recover panics:
+//
+//	func handleJob(job Job) (err error) {
+//		defer wrap.Recover(&err, "job handler panicked")
+//		// ...
+//	}
+//
+// The error carries the panic value, a stack trace of the panic and a "panic=true" log attribute
+// (see [PanicError]). If there was no panic, the error is left unchanged. If there was, it replaces
+// any error already stored in errPtr.
+func Recover(errPtr *error, message string) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	// Recover runs on top of the panicking stack, so the stack trace includes the location of the
+	// panic
+	*errPtr = runWrapHook(newPanicError(value, message, CaptureStack(1)))
+}
+
+// NewPanicError converts the given value recovered from a panic to a [PanicError] wrapped with the
+// given message, with a stack trace starting at the caller. Call it from the deferred function
+// that recovered the panic, so that the stack trace includes the location of the panic. Use
+// [Recover] instead for the common case of recovering to an error return value.
+func NewPanicError(value any, message string) *PanicError {
+	return newPanicError(value, message, CaptureStack(1))
+}
+
+func newPanicError(value any, message string, stack StackTrace) *PanicError {
+	valueErr, ok := value.(error)
+	if !ok {
+		valueErr = fmt.Errorf("%v", value)
+	}
+
+	return &PanicError{
+		wrappedError: wrappedError{
+			wrapped: &stackError{wrapped: valueErr, stack: stack},
+			message: message,
+			caller:  recordCaller(),
+		},
+		value: value,
+		stack: stack,
+	}
+}
+
+// PanicError is an error converted from a recovered panic (see [Recover] and [NewPanicError]). It
+// lets observability code treat panics specially (e.g. page on panics, but not on ordinary
+// errors):
+//
+//	var panicErr *wrap.PanicError
+//	if errors.As(err, &panicErr) {
+//		pager.Alert(panicErr.PanicValue(), panicErr.StackTrace())
+//	}
+//
+// It wraps the panic value with the message of the error: if the panic value is an error, it is
+// wrapped directly (so it still works with [errors.Is] and [errors.As]), otherwise it is formatted
+// with [fmt.Sprint]. The stack trace is also available through [Stack], and the error carries a
+// "panic=true" log attribute (with the key [PanicKey]).
+type PanicError struct {
+	wrappedError
+	value any
+	stack StackTrace
+}
+
+// PanicValue returns the value that the panic was called with.
+func (err *PanicError) PanicValue() any {
+	return err.value
+}
+
+// StackTrace returns the stack trace of the goroutine where the panic was recovered, which
+// includes the location of the panic.
+func (err *PanicError) StackTrace() StackTrace {
+	return err.stack
+}
+
+// LogAttrs returns the "panic=true" log attribute, for logging libraries that look for this method
+// (such as [hermannm.dev/devlog/log]).
+func (err *PanicError) LogAttrs() []slog.Attr {
+	return []slog.Attr{slog.Bool(PanicKey, true)}
+}
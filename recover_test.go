@@ -0,0 +1,66 @@
+package wrap_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestRecover(t *testing.T) {
+	err := panickingJob("something went wrong")
+
+	expected := `job handler panicked
+- something went wrong`
+
+	assertEqualErrorStrings(t, err, expected)
+	assertEqualAttrSlices(t, wrap.Attrs(err), []slog.Attr{slog.Bool(wrap.PanicKey, true)})
+
+	stack, ok := wrap.Stack(err)
+	if !ok {
+		t.Fatal("expected recovered error to have a stack trace")
+	}
+	rendered := stack.Render(wrap.StackFormatGo)
+	if !strings.Contains(rendered, "panickingJob") {
+		t.Errorf("expected stack trace to include the panicking function, got:\n%s", rendered)
+	}
+
+	var panicErr *wrap.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatal("expected recovered error to be a PanicError")
+	}
+	if value := panicErr.PanicValue(); value != "something went wrong" {
+		t.Errorf("unexpected panic value %v", value)
+	}
+	if !slices.Equal(panicErr.StackTrace(), stack) {
+		t.Error("expected StackTrace to match the stack trace returned by Stack")
+	}
+}
+
+func TestRecoverErrorValue(t *testing.T) {
+	err := panickingJob(io.ErrUnexpectedEOF)
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("expected errors.Is to return true for error panic value")
+	}
+}
+
+func TestRecoverWithoutPanic(t *testing.T) {
+	err := func() (err error) {
+		defer wrap.Recover(&err, "job handler panicked")
+		return io.EOF
+	}()
+
+	if err != io.EOF {
+		t.Errorf("expected error to be left unchanged, got %v", err)
+	}
+}
+
+func panickingJob(value any) (err error) {
+	defer wrap.Recover(&err, "job handler panicked")
+	panic(value)
+}
@@ -0,0 +1,76 @@
+package wrap
+
+import (
+	"errors"
+)
+
+// The wrapping message displayed for errors marked with MarkRemote, to divide local and remote
+// layers.
+const remoteDivider = "↯ remote"
+
+// MarkRemote marks the given error as coming from a remote service, for use when rehydrating a
+// serialized error received from another service. When the error is wrapped locally, the formatter
+// displays a "↯ remote" divider between the local layers and the remote layers, so that it is clear
+// where the error crossed the service boundary:
+//
+//	remoteErr := wrap.MarkRemote(wrap.Error(errors.New("no rows"), "user lookup failed"))
+//	wrapped := wrap.Error(remoteErr, "failed to fetch user profile")
+//	fmt.Println(wrapped)
+//	// failed to fetch user profile
+//	// - ↯ remote
+//	// - user lookup failed
+//	// - no rows
+//
+// The divider is only displayed by the formatter, and is not a layer of the error for [Messages],
+// [Walk] or [Fingerprint]. Use [IsRemote] to check if an error chain contains a remote error. If
+// the given error is nil, MarkRemote returns nil.
+func MarkRemote(err error) error {
+	if err == nil {
+		return nil
+	}
+	return withNetError(&remoteError{wrapped: err})
+}
+
+// IsRemote returns true if the given error or any error it wraps was marked with [MarkRemote].
+func IsRemote(err error) bool {
+	var remoteErr *remoteError
+	return errors.As(err, &remoteErr)
+}
+
+type remoteError struct {
+	wrapped error
+}
+
+func (err *remoteError) Error() string {
+	var builder errorBuilder
+	builder.WriteString(remoteDivider)
+	builder.writeErrorListItem(err.wrapped, 1, false)
+	return builder.String()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *remoteError) Unwrap() error {
+	return err.wrapped
+}
+
+// The remote divider is not a layer of its own, so that it doesn't change the messages, fingerprint
+// or walked layers of the error. The formatter displays it (see unwrapMarkersToRemote), but other
+// consumers skip it like other markers.
+func (err *remoteError) unwrapMarker() error {
+	return err.wrapped
+}
+
+// Like unwrapMarkers, but stops after a remote marker, and returns whether one was found, so that
+// the formatter can display the remote divider in its place.
+func unwrapMarkersToRemote(err error) (unwrapped error, remote bool) {
+	for {
+		switch marker := err.(type) {
+		case *remoteError:
+			return marker.wrapped, true
+		case markerError:
+			err = marker.unwrapMarker()
+		default:
+			return err, false
+		}
+	}
+}
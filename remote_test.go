@@ -0,0 +1,79 @@
+package wrap_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestMarkRemote(t *testing.T) {
+	remoteErr := wrap.MarkRemote(wrap.Error(errors.New("no rows"), "user lookup failed"))
+	wrapped := wrap.Error(remoteErr, "failed to fetch user profile")
+
+	expected := `failed to fetch user profile
+- ↯ remote
+- user lookup failed
+- no rows`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if !wrap.IsRemote(wrapped) {
+		t.Error("expected IsRemote to return true for wrapped remote error")
+	}
+}
+
+func TestMarkRemoteInList(t *testing.T) {
+	remoteErr := wrap.MarkRemote(errors.New("quota exceeded"))
+	wrapped := wrap.Errors("sync failed", remoteErr, errors.New("local cache unavailable"))
+
+	expected := `sync failed
+- ↯ remote
+  - quota exceeded
+- local cache unavailable`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
+
+func TestRemoteDividerIsNotLayer(t *testing.T) {
+	newErr := func(remote bool) error {
+		err := wrap.Error(errors.New("no rows"), "user lookup failed")
+		if remote {
+			err = wrap.MarkRemote(err)
+		}
+		return wrap.Error(err, "failed to fetch user profile")
+	}
+	local, remote := newErr(false), newErr(true)
+
+	expectedMessages := []string{"failed to fetch user profile", "user lookup failed", "no rows"}
+	if messages := wrap.Messages(remote); !slices.Equal(messages, expectedMessages) {
+		t.Errorf("unexpected messages; got %v, want %v", messages, expectedMessages)
+	}
+
+	if wrap.Fingerprint(remote) != wrap.Fingerprint(local) {
+		t.Error("expected remote marker to not change fingerprint")
+	}
+
+	var layerCount int
+	wrap.Walk(remote, func(layer wrap.Layer) bool {
+		if layer.Message == "↯ remote" {
+			t.Error("expected Walk to not visit remote divider")
+		}
+		layerCount++
+		return true
+	})
+	if layerCount != 3 {
+		t.Errorf("expected Walk to visit 3 layers, got %d", layerCount)
+	}
+}
+
+func TestIsRemoteLocalError(t *testing.T) {
+	err := wrap.Error(errors.New("error"), "wrapped error")
+	if wrap.IsRemote(err) {
+		t.Error("expected IsRemote to return false for local error")
+	}
+	if wrap.MarkRemote(nil) != nil {
+		t.Error("expected MarkRemote to return nil for nil error")
+	}
+}
@@ -0,0 +1,42 @@
+package wrap
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// RequestIDKey is the log attribute key for request IDs, used by the errors that
+// [hermannm.dev/wrap/ctxwrap] creates from contexts with a request ID.
+const RequestIDKey = "request_id"
+
+// RequestID returns the request ID attached to the given error or the errors it wraps, if any. The
+// request ID is read from the log attribute with the key [RequestIDKey] (see [Attrs]), which is
+// attached automatically to errors created by [hermannm.dev/wrap/ctxwrap] from a context with a
+// request ID. If there are several request IDs in the chain, the outermost one is returned. Request
+// IDs that repeat an earlier one (such as from nested errors created from the same request) are
+// only included once in Attrs.
+func RequestID(err error) (requestID string, ok bool) {
+	for _, attr := range Attrs(err) {
+		if attr.Key == RequestIDKey {
+			return attr.Value.String(), true
+		}
+	}
+	return "", false
+}
+
+// Removes the request ID attributes (see RequestIDKey) that repeat the value of an earlier one from
+// the given merged attributes, so that nested errors created from the same request include the
+// request ID once. Only string values are compared, since other values may not be comparable.
+func removeRepeatedRequestIDs(attrs []slog.Attr) []slog.Attr {
+	var seen []string
+	return slices.DeleteFunc(attrs, func(attr slog.Attr) bool {
+		if attr.Key != RequestIDKey || attr.Value.Kind() != slog.KindString {
+			return false
+		}
+		if slices.Contains(seen, attr.Value.String()) {
+			return true
+		}
+		seen = append(seen, attr.Value.String())
+		return false
+	})
+}
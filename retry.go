@@ -0,0 +1,69 @@
+package wrap
+
+import (
+	"net"
+)
+
+// Retryable wraps the given error with a message for context, and marks it as retryable, i.e. a
+// transient failure where retrying the operation may succeed (such as a failed call to an
+// overloaded service). Check the mark with [IsRetryable].
+//
+// Example:
+//
+//	err := errors.New("503 Service Unavailable")
+//	wrapped := wrap.Retryable(err, "failed to fetch exchange rates")
+//	fmt.Println(wrapped)
+//	// failed to fetch exchange rates
+//	// - 503 Service Unavailable
+//
+// The returned error implements the Unwrap method from the standard errors package, so it works
+// with [errors.Is] and [errors.As].
+func Retryable(wrapped error, message string) error {
+	err := &retryableError{
+		wrapped: &wrappedError{wrapped: wrapped, message: message, caller: recordCaller()},
+	}
+	return runWrapHook(withNetErrorFrom(err, wrapped))
+}
+
+// IsRetryable returns true if the given error or any error it wraps was marked as retryable with
+// [Retryable] (or [Builder.Retryable], or is a [Sentinel] created with [WithRetryable]), or is a
+// [net.Error] that reports a timeout or a temporary failure. This lets retry loops classify errors
+// without matching on error strings.
+func IsRetryable(err error) (retryable bool) {
+	forEachInChain(err, func(err error) {
+		switch err := err.(type) {
+		case *retryableError:
+			retryable = true
+		case *Sentinel:
+			if err.retryable {
+				retryable = true
+			}
+		case net.Error:
+			// Temporary is deprecated, but it is still the only transience signal that some
+			// libraries give
+			if err.Timeout() || err.Temporary() {
+				retryable = true
+			}
+		}
+	})
+	return retryable
+}
+
+// Marks a wrapped error as retryable. The mark is attached as a marker around the layer that it
+// applies to, so it doesn't change how the error is formatted.
+type retryableError struct {
+	wrapped error
+}
+
+func (err *retryableError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *retryableError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *retryableError) unwrapMarker() error {
+	return err.wrapped
+}
@@ -0,0 +1,60 @@
+package wrap_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestRetryable(t *testing.T) {
+	err := errors.New("503 Service Unavailable")
+	inner := wrap.Retryable(err, "failed to fetch exchange rates")
+	outer := wrap.Error(inner, "failed to convert currency")
+
+	expected := `failed to convert currency
+- failed to fetch exchange rates
+- 503 Service Unavailable`
+
+	assertEqualErrorStrings(t, outer, expected)
+
+	if !wrap.IsRetryable(outer) {
+		t.Error("expected IsRetryable to return true for error wrapping retryable error")
+	}
+	if !errors.Is(outer, err) {
+		t.Error("expected errors.Is to return true for wrapped error")
+	}
+	if wrap.IsRetryable(wrap.Error(err, "failed to fetch exchange rates")) {
+		t.Error("expected IsRetryable to return false for unmarked error")
+	}
+}
+
+func TestIsRetryableWithNetError(t *testing.T) {
+	timeout := &net.OpError{Op: "dial", Net: "tcp", Err: context.DeadlineExceeded}
+	if !wrap.IsRetryable(wrap.Error(timeout, "failed to connect")) {
+		t.Error("expected IsRetryable to return true for net.Error with timeout")
+	}
+
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	if wrap.IsRetryable(wrap.Error(refused, "failed to connect")) {
+		t.Error("expected IsRetryable to return false for net.Error without timeout")
+	}
+}
+
+func TestRetryableBuilderAndSentinel(t *testing.T) {
+	err := wrap.New(errors.New("error")).Message("wrapped error").With(wrap.WithRetryable()).Err()
+	if !wrap.IsRetryable(err) {
+		t.Error("expected IsRetryable to return true for error built with WithRetryable")
+	}
+	assertEqualErrorStrings(t, err, "wrapped error\n- error")
+
+	errRateLimited := wrap.NewSentinel("rate limited", wrap.WithRetryable())
+	if !wrap.IsRetryable(wrap.Error(errRateLimited, "failed to send email")) {
+		t.Error("expected IsRetryable to return true for retryable sentinel")
+	}
+	if wrap.IsRetryable(wrap.NewSentinel("not found")) {
+		t.Error("expected IsRetryable to return false for sentinel without WithRetryable")
+	}
+}
@@ -0,0 +1,46 @@
+package wrap
+
+// RootCause returns the deepest error in the given error's chain, i.e. the first error that doesn't
+// wrap any other errors. For multi-errors (such as the ones created by [Errors]), it follows the
+// first wrapped error; use [RootCauses] to get the root causes of all branches. If the given error
+// is nil, RootCause returns nil.
+//
+// Example:
+//
+//	err := errors.New("connection refused")
+//	wrapped := wrap.Error(wrap.Error(err, "query failed"), "failed to fetch user")
+//	fmt.Println(wrap.RootCause(wrapped) == err)
+//	// true
+func RootCause(err error) error {
+	for err != nil {
+		switch unwrappable := err.(type) {
+		case interface{ Unwrap() error }:
+			wrapped := unwrappable.Unwrap()
+			if wrapped == nil {
+				return err
+			}
+			err = wrapped
+		case interface{ Unwrap() []error }:
+			wrapped := unwrappable.Unwrap()
+			if len(wrapped) == 0 {
+				return err
+			}
+			err = wrapped[0]
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// RootCauses returns the deepest errors in the given error tree, i.e. the errors that don't wrap
+// any other errors, following all branches of multi-errors in order.
+func RootCauses(err error) []error {
+	var rootCauses []error
+	forEachInChain(err, func(err error) {
+		if isLeaf(err) {
+			rootCauses = append(rootCauses, err)
+		}
+	})
+	return rootCauses
+}
@@ -0,0 +1,48 @@
+package wrap_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestRootCause(t *testing.T) {
+	err := errors.New("connection refused")
+	wrapped := wrap.AddStack(wrap.Error(wrap.Error(err, "query failed"), "failed to fetch user"))
+
+	if rootCause := wrap.RootCause(wrapped); rootCause != err {
+		t.Errorf("unexpected root cause: %v", rootCause)
+	}
+
+	pathErr := &fs.PathError{Op: "open", Path: "config.json"}
+	if rootCause := wrap.RootCause(wrap.Error(pathErr, "failed")); rootCause != pathErr {
+		t.Errorf("expected error with nil Unwrap to be root cause, got %v", rootCause)
+	}
+
+	if wrap.RootCause(nil) != nil {
+		t.Error("expected root cause of nil error to be nil")
+	}
+}
+
+func TestRootCauses(t *testing.T) {
+	err1 := errors.New("username too long")
+	err2 := errors.New("invalid email")
+	err3 := errors.New("timeout")
+	wrapped := wrap.Errors(
+		"user creation failed",
+		wrap.Error(err1, "invalid username"),
+		wrap.Errors("validation failed", err2, err3),
+	)
+
+	if rootCause := wrap.RootCause(wrapped); rootCause != err1 {
+		t.Errorf("expected RootCause to follow first branch, got %v", rootCause)
+	}
+
+	rootCauses := wrap.RootCauses(wrapped)
+	if len(rootCauses) != 3 ||
+		rootCauses[0] != err1 || rootCauses[1] != err2 || rootCauses[2] != err3 {
+		t.Errorf("unexpected root causes: %v", rootCauses)
+	}
+}
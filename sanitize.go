@@ -0,0 +1,67 @@
+package wrap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+var attrSanitizer atomic.Pointer[func(value slog.Value) slog.Value]
+
+// SetAttrSanitizer sets a hook that is applied to the value of every log attribute attached to an
+// error (e.g. through [ErrorWithAttrs]), before the attribute is stored on the error. This lets you
+// normalize attribute values up front, so that serialized output is the same regardless of which
+// log encoder ends up handling the error. [NormalizeAttrValue] is a ready-made sanitizer for this.
+//
+// Pass nil to remove a previously set sanitizer. The sanitizer must be safe for concurrent use. It
+// is never called while this package holds a lock, so it may create wrapped errors itself (though
+// errors with attributes will run the sanitizer again).
+func SetAttrSanitizer(sanitizer func(value slog.Value) slog.Value) {
+	if sanitizer == nil {
+		attrSanitizer.Store(nil)
+	} else {
+		attrSanitizer.Store(&sanitizer)
+	}
+	attrsConfigVersion.Add(1)
+}
+
+// NormalizeAttrValue converts log attribute values to encoder-agnostic representations, for use
+// with [SetAttrSanitizer]:
+//   - [slog.LogValuer] values are resolved
+//   - Errors are converted to their error strings
+//   - [time.Time] values are converted to RFC 3339 strings (with nanoseconds, if non-zero)
+//   - Byte slices are converted to a group with the size and SHA-256 hash of the bytes, to avoid
+//     storing large payloads on errors
+//   - Groups are normalized recursively
+//
+// Other values are returned as-is.
+func NormalizeAttrValue(value slog.Value) slog.Value {
+	value = value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindTime:
+		return slog.StringValue(value.Time().Format(time.RFC3339Nano))
+	case slog.KindGroup:
+		group := value.Group()
+		normalized := make([]slog.Attr, len(group))
+		for i, attr := range group {
+			normalized[i] = slog.Attr{Key: attr.Key, Value: NormalizeAttrValue(attr.Value)}
+		}
+		return slog.GroupValue(normalized...)
+	case slog.KindAny:
+		switch anyValue := value.Any().(type) {
+		case error:
+			return slog.StringValue(anyValue.Error())
+		case []byte:
+			hash := sha256.Sum256(anyValue)
+			return slog.GroupValue(
+				slog.Int("size", len(anyValue)),
+				slog.String("sha256", hex.EncodeToString(hash[:])),
+			)
+		}
+	}
+
+	return value
+}
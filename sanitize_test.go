@@ -0,0 +1,47 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAttrSanitizer(t *testing.T) {
+	wrap.SetAttrSanitizer(wrap.NormalizeAttrValue)
+	defer wrap.SetAttrSanitizer(nil)
+
+	wrapped := wrap.ErrorWithAttrs(
+		errors.New("error"),
+		"wrapped error",
+		"cause", errors.New("cause"),
+		"time", time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+		"body", []byte("hello"),
+		slog.Group("group", "nested_time", time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)),
+		"count", 2,
+	)
+
+	assertEqualAttrs(t, wrapped, []slog.Attr{
+		slog.String("cause", "cause"),
+		slog.String("time", "2024-01-01T12:00:00Z"),
+		slog.Group(
+			"body",
+			slog.Int("size", 5),
+			slog.String(
+				"sha256",
+				"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			),
+		),
+		slog.Group("group", slog.String("nested_time", "2024-01-02T00:00:00Z")),
+		slog.Int("count", 2),
+	})
+}
+
+func TestNoAttrSanitizer(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	wrapped := wrap.ErrorWithAttrs(errors.New("error"), "wrapped error", "time", createdAt)
+
+	assertEqualAttrs(t, wrapped, []slog.Attr{slog.Time("time", createdAt)})
+}
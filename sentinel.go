@@ -0,0 +1,74 @@
+package wrap
+
+import (
+	"log/slog"
+)
+
+// Sentinel is a constant error defined at package scope, that carries an error code, a log level
+// and structured log attributes in addition to its message. Create it with [NewSentinel].
+//
+// Compare errors against a sentinel with [errors.Is]. The code and attributes of the sentinel are
+// included when logging any error that wraps it, and the code is returned by [CodeOf] (unless an
+// outer layer has its own code). The same goes for the level and [LevelOf]. A sentinel created with
+// [WithRetryable] is reported as retryable by [IsRetryable].
+type Sentinel struct {
+	message   string
+	code      string
+	level     *slog.Level
+	retryable bool
+	attrs     []slog.Attr
+}
+
+// NewSentinel creates a sentinel error with the given message. The code, level and attributes of
+// the sentinel are set with the [WithCode], [WithLevel] and [WithAttrs] options:
+//
+//	var ErrQuotaExceeded = wrap.NewSentinel(
+//		"quota exceeded",
+//		wrap.WithCode("QUOTA"),
+//		wrap.WithAttrs(slog.String("domain", "billing")),
+//	)
+//
+//	func chargeCustomer() error {
+//		// ...
+//		return wrap.Error(ErrQuotaExceeded, "failed to charge customer")
+//	}
+//
+// Since a sentinel is created once and shared by every error that wraps it, options that only make
+// sense for a single occurrence of an error ([WithStack], [WithErrorID], [WithPrivateAttrs] and
+// [WithContext]) are ignored. Wrap the sentinel with [E] or [New] to use them.
+func NewSentinel(message string, options ...Option) *Sentinel {
+	var builder Builder
+	builder.With(options...)
+
+	return &Sentinel{
+		message:   message,
+		code:      builder.code,
+		level:     builder.level,
+		retryable: builder.retryable,
+		attrs:     newAttrs(builder.attrs),
+	}
+}
+
+func (err *Sentinel) Error() string {
+	return err.message
+}
+
+// LogAttrs returns the code and structured log attributes of the sentinel, for logging libraries
+// that look for this method (such as [hermannm.dev/devlog/log]).
+func (err *Sentinel) LogAttrs() []slog.Attr {
+	if err.code == "" {
+		return err.attrs
+	}
+	return append([]slog.Attr{slog.String(CodeKey, err.code)}, err.attrs...)
+}
+
+func (err *Sentinel) errorCode() (code string, ok bool) {
+	return err.code, err.code != ""
+}
+
+func (err *Sentinel) errorLevel() (level slog.Level, ok bool) {
+	if err.level == nil {
+		return 0, false
+	}
+	return *err.level, true
+}
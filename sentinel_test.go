@@ -0,0 +1,60 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+var errQuotaExceeded = wrap.NewSentinel(
+	"quota exceeded",
+	wrap.WithCode("QUOTA"),
+	wrap.WithAttrs(slog.String("domain", "billing")),
+)
+
+func TestSentinel(t *testing.T) {
+	inner := wrap.ErrorWithAttrs(errQuotaExceeded, "failed to charge customer", "customer_id", 42)
+	outer := wrap.Error(inner, "failed to renew subscription")
+
+	expected := `failed to renew subscription
+- failed to charge customer
+- quota exceeded`
+
+	assertEqualErrorStrings(t, outer, expected)
+	assertEqualAttrSlices(t, wrap.Attrs(outer), []slog.Attr{
+		slog.Int("customer_id", 42),
+		slog.String("code", "QUOTA"),
+		slog.String("domain", "billing"),
+	})
+
+	if !errors.Is(outer, errQuotaExceeded) {
+		t.Error("expected errors.Is to return true for sentinel")
+	}
+	if errors.Is(outer, wrap.NewSentinel("quota exceeded")) {
+		t.Error("expected errors.Is to return false for other sentinel with same message")
+	}
+	if code, ok := wrap.CodeOf(outer); !ok || code != "QUOTA" {
+		t.Errorf("unexpected code; got (%q, %t)", code, ok)
+	}
+}
+
+func TestSentinelCodeIsOverriddenByOuterCode(t *testing.T) {
+	err := wrap.ErrorWithCode(errQuotaExceeded, "SUBSCRIPTION_FAILED", "failed to renew")
+	if code, _ := wrap.CodeOf(err); code != "SUBSCRIPTION_FAILED" {
+		t.Errorf("expected outer code, got %q", code)
+	}
+}
+
+func TestSentinelWithoutCode(t *testing.T) {
+	errNotFound := wrap.NewSentinel("not found")
+	err := wrap.ErrorWithCode(wrap.Error(errNotFound, "lookup failed"), "NOT_FOUND", "fetch failed")
+
+	if code, ok := wrap.CodeOf(errNotFound); ok {
+		t.Errorf("expected no code for sentinel without code, got %q", code)
+	}
+	if code, _ := wrap.CodeOf(err); code != "NOT_FOUND" {
+		t.Errorf("expected code from wrapping layer, got %q", code)
+	}
+}
@@ -0,0 +1,135 @@
+package wrap
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"unsafe"
+
+	"hermannm.dev/wrap/errschema"
+)
+
+// Approximate fixed overhead of an error value in the chain (interface header, struct fields and
+// allocation overhead), used by EstimateSize.
+const errorOverhead = 48
+
+// Approximate size of a context attached to an error. Contexts may retain arbitrary values, which
+// can't be measured, so this only accounts for the context itself.
+const contextOverhead = 64
+
+// EstimateSize approximates the number of bytes retained by the given error and all errors it
+// wraps: messages, log attributes, stack traces and attached contexts. It is meant for catching
+// errors that accidentally retain large values (such as request bodies attached as attributes),
+// not for exact accounting. Values that can't be measured (such as the values stored in a context)
+// are counted with a fixed estimate.
+//
+// Use [SizeWarningHook] with [SetWrapHook] to get warnings when large errors are created.
+func EstimateSize(err error) int {
+	size := 0
+	forEachInChain(err, func(err error) {
+		size += errorOverhead
+
+		switch err := err.(type) {
+		case interface{ WrappingMessage() string }:
+			size += len(err.WrappingMessage())
+		case markerError:
+		default:
+			if !isUnwrappable(err) {
+				size += len(err.Error())
+			}
+		}
+
+		if withAttrs, ok := err.(hasLogAttrs); ok {
+			for _, attr := range withAttrs.LogAttrs() {
+				size += estimateAttrSize(attr)
+			}
+		}
+		if stackErr, ok := err.(*stackError); ok {
+			size += len(stackErr.stack) * int(unsafe.Sizeof(uintptr(0)))
+		}
+		if _, ok := err.(errschema.ErrorWithContext); ok {
+			size += contextOverhead
+		}
+	})
+	return size
+}
+
+func estimateAttrSize(attr slog.Attr) int {
+	size := len(attr.Key) + int(unsafe.Sizeof(attr))
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		size += len(attr.Value.String())
+	case slog.KindGroup:
+		for _, groupAttr := range attr.Value.Group() {
+			size += estimateAttrSize(groupAttr)
+		}
+	case slog.KindAny:
+		switch value := attr.Value.Any().(type) {
+		case []byte:
+			size += len(value)
+		case string:
+			size += len(value)
+		case error:
+			size += EstimateSize(value)
+		case []string:
+			for _, element := range value {
+				size += len(element) + int(unsafe.Sizeof(element))
+			}
+		}
+	}
+
+	return size
+}
+
+func isUnwrappable(err error) bool {
+	switch err.(type) {
+	case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		return true
+	default:
+		return false
+	}
+}
+
+var wrapHook atomic.Pointer[func(err error)]
+
+// SetWrapHook sets a hook that is called with every error created by the wrapping functions in this
+// package ([Error], [Errorf], [Errors], [ErrorWithAttrs], [NewErrorWithAttrs], [AddAttrs],
+// [ErrorWithCode], [ErrorWithLevel], [ErrorWithPublicMessage], [Retryable], [WithData] and
+// [Builder.Err]), including those called by [hermannm.dev/wrap/ctxwrap]. See [SizeWarningHook] for
+// a ready-made hook.
+//
+// Pass nil to remove a previously set hook. The hook must be safe for concurrent use, and should be
+// cheap, since it runs on every wrap.
+func SetWrapHook(hook func(err error)) {
+	if hook == nil {
+		wrapHook.Store(nil)
+	} else {
+		wrapHook.Store(&hook)
+	}
+}
+
+// Finishes a newly created error: adds the net.Error marker if the error wraps a net.Error (see
+// withNetError), and runs the hook set with SetWrapHook.
+func runWrapHook(err error) error {
+	err = withNetError(err)
+	if hook := wrapHook.Load(); hook != nil {
+		(*hook)(err)
+	}
+	return err
+}
+
+// SizeWarningHook returns a hook for [SetWrapHook] that calls warn for every created error whose
+// [EstimateSize] exceeds the given threshold in bytes.
+//
+// Example:
+//
+//	wrap.SetWrapHook(wrap.SizeWarningHook(1<<20, func(err error, size int) {
+//		slog.Warn("created error larger than 1 MiB", "size", size, "error", err)
+//	}))
+func SizeWarningHook(threshold int, warn func(err error, size int)) func(err error) {
+	return func(err error) {
+		if size := EstimateSize(err); size > threshold {
+			warn(err, size)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package wrap_test
+
+import (
+	"errors"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestEstimateSize(t *testing.T) {
+	err := errors.New("error")
+	small := wrap.ErrorWithAttrs(err, "wrapped error", "id", 123)
+	large := wrap.ErrorWithAttrs(err, "wrapped error", "body", make([]byte, 1<<20))
+
+	smallSize := wrap.EstimateSize(small)
+	if smallSize <= len("wrapped error")+len("error") || smallSize > 1024 {
+		t.Errorf("unexpected size estimate for small error: %d", smallSize)
+	}
+
+	largeSize := wrap.EstimateSize(wrap.Errors("outer", large, errors.New("other error")))
+	if largeSize < 1<<20 {
+		t.Errorf("expected size estimate to include large attribute, got %d", largeSize)
+	}
+
+	if size := wrap.EstimateSize(nil); size != 0 {
+		t.Errorf("expected size estimate of nil error to be 0, got %d", size)
+	}
+}
+
+func TestSizeWarningHook(t *testing.T) {
+	var warnings []int
+	wrap.SetWrapHook(wrap.SizeWarningHook(1024, func(err error, size int) {
+		warnings = append(warnings, size)
+	}))
+	defer wrap.SetWrapHook(nil)
+
+	err := errors.New("error")
+	_ = wrap.Error(err, "small error")
+	_ = wrap.ErrorWithAttrs(err, "large error", "body", make([]byte, 2048))
+
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 size warning, got %d", len(warnings))
+	}
+	if warnings[0] < 2048 {
+		t.Errorf("expected size warning to include large attribute, got %d", warnings[0])
+	}
+}
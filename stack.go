@@ -0,0 +1,157 @@
+package wrap
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// AddStack attaches a stack trace of the caller to the given error, without adding a message. The
+// stack trace does not change how the error is displayed, but can be retrieved with [Stack] by
+// reporters and serializers, which choose how to render it (see [StackTrace.Render] and
+// [StackTrace.MarshalJSON]).
+//
+// The returned error implements the Unwrap method from the standard errors package, so it works
+// with [errors.Is] and [errors.As].
+func AddStack(wrapped error) error {
+	return withNetError(&stackError{wrapped: wrapped, stack: CaptureStack(1)})
+}
+
+// Stack returns the stack trace attached to the given error or the errors it wraps with
+// [AddStack], if any. If there are several stack traces in the chain, the innermost one is
+// returned, since it is the closest to where the error originated.
+func Stack(err error) (stack StackTrace, ok bool) {
+	for {
+		var stackErr *stackError
+		if !errors.As(err, &stackErr) {
+			return stack, ok
+		}
+		stack, ok = stackErr.stack, true
+		err = stackErr.wrapped
+	}
+}
+
+// StackTrace is a captured call stack, stored as program counters and resolved to frames lazily.
+type StackTrace []uintptr
+
+// Maximum number of frames captured by CaptureStack.
+const maxStackDepth = 64
+
+// CaptureStack captures the stack trace of the calling goroutine. The skip argument is the number of
+// stack frames to skip before recording, with 0 identifying the caller of CaptureStack.
+func CaptureStack(skip int) StackTrace {
+	var programCounters [maxStackDepth]uintptr
+	// +2 to skip runtime.Callers and CaptureStack itself
+	count := runtime.Callers(skip+2, programCounters[:])
+	return StackTrace(programCounters[:count:count])
+}
+
+// StackFrame is a single resolved frame of a [StackTrace].
+type StackFrame struct {
+	// Function is the package path-qualified function name, e.g. "hermannm.dev/wrap.AddStack".
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Frames resolves the program counters of the stack trace to frames, innermost call first.
+func (stack StackTrace) Frames() []StackFrame {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	frames := make([]StackFrame, 0, len(stack))
+	runtimeFrames := runtime.CallersFrames(stack)
+	for {
+		frame, more := runtimeFrames.Next()
+		frames = append(
+			frames,
+			StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line},
+		)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// StackFormat selects the text format used by [StackTrace.Render].
+type StackFormat int
+
+const (
+	// StackFormatGo renders the stack trace like Go's own panic output, with the function name on
+	// one line followed by an indented file:line on the next:
+	//
+	//	main.handleRequest
+	//		/app/main.go:42
+	StackFormatGo StackFormat = iota
+
+	// StackFormatJava renders the stack trace like a Java exception stack trace, for tools that
+	// expect that format (such as the OpenTelemetry exception.stacktrace attribute):
+	//
+	//	at main.handleRequest(/app/main.go:42)
+	StackFormatJava
+)
+
+// Render renders the stack trace as a string in the given format, with one frame per line
+// (innermost call first). For a JSON array of frames, marshal the stack trace with [json.Marshal]
+// instead (see [StackTrace.MarshalJSON]).
+func (stack StackTrace) Render(format StackFormat) string {
+	var builder strings.Builder
+
+	for i, frame := range stack.Frames() {
+		if i != 0 {
+			builder.WriteByte('\n')
+		}
+
+		switch format {
+		case StackFormatJava:
+			builder.WriteString("at ")
+			builder.WriteString(frame.Function)
+			builder.WriteByte('(')
+			builder.WriteString(frame.File)
+			builder.WriteByte(':')
+			builder.WriteString(strconv.Itoa(frame.Line))
+			builder.WriteByte(')')
+		default:
+			builder.WriteString(frame.Function)
+			builder.WriteString("\n\t")
+			builder.WriteString(frame.File)
+			builder.WriteByte(':')
+			builder.WriteString(strconv.Itoa(frame.Line))
+		}
+	}
+
+	return builder.String()
+}
+
+// MarshalJSON implements [json.Marshaler], encoding the stack trace as a JSON array of frames
+// (see [StackFrame]).
+func (stack StackTrace) MarshalJSON() ([]byte, error) {
+	frames := stack.Frames()
+	if frames == nil {
+		frames = []StackFrame{}
+	}
+	return json.Marshal(frames)
+}
+
+type stackError struct {
+	wrapped error
+	stack   StackTrace
+	mergedAttrsCache
+}
+
+func (err *stackError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *stackError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *stackError) unwrapMarker() error {
+	return err.wrapped
+}
@@ -0,0 +1,85 @@
+package wrap_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestAddStack(t *testing.T) {
+	err := errors.New("error")
+	withStack := wrap.AddStack(err)
+	wrapped := wrap.Error(withStack, "wrapped error")
+
+	expected := `wrapped error
+- error`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	stack, ok := wrap.Stack(wrapped)
+	if !ok {
+		t.Fatal("expected Stack to find stack trace")
+	}
+
+	frames := stack.Frames()
+	if len(frames) == 0 {
+		t.Fatal("expected stack trace to have frames")
+	}
+	if frames[0].Function != "hermannm.dev/wrap_test.TestAddStack" {
+		t.Errorf("expected first frame to be the caller of AddStack, got %s", frames[0].Function)
+	}
+	if !strings.HasSuffix(frames[0].File, "stack_test.go") || frames[0].Line == 0 {
+		t.Errorf("unexpected file and line of first frame: %s:%d", frames[0].File, frames[0].Line)
+	}
+
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to return true for error with stack trace")
+	}
+}
+
+func TestStackInnermost(t *testing.T) {
+	inner := wrap.AddStack(errors.New("error"))
+	outer := wrap.AddStack(wrap.Error(inner, "wrapped error"))
+
+	expected, _ := wrap.Stack(inner)
+	actual, _ := wrap.Stack(outer)
+	if len(actual) == 0 || &actual[0] != &expected[0] {
+		t.Error("expected Stack to return innermost stack trace")
+	}
+
+	if _, ok := wrap.Stack(errors.New("error")); ok {
+		t.Error("expected Stack to return false for error without stack trace")
+	}
+}
+
+func TestStackRender(t *testing.T) {
+	stack := wrap.CaptureStack(0)
+	frame := stack.Frames()[0]
+	location := frame.File + ":" + strconv.Itoa(frame.Line)
+
+	goFormat := stack.Render(wrap.StackFormatGo)
+	if !strings.HasPrefix(goFormat, frame.Function+"\n\t"+location+"\n") {
+		t.Errorf("unexpected Go stack trace format:\n%s", goFormat)
+	}
+
+	javaFormat := stack.Render(wrap.StackFormatJava)
+	if !strings.HasPrefix(javaFormat, "at "+frame.Function+"("+location+")\n") {
+		t.Errorf("unexpected Java stack trace format:\n%s", javaFormat)
+	}
+
+	jsonFormat, err := json.Marshal(stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames []wrap.StackFrame
+	if err := json.Unmarshal(jsonFormat, &frames); err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) == 0 || frames[0] != frame {
+		t.Errorf("unexpected JSON stack trace format: %s", jsonFormat)
+	}
+}
@@ -0,0 +1,123 @@
+package wrap
+
+import (
+	"fmt"
+	"sync"
+)
+
+// StreamCollector aggregates errors from very large batch jobs (such as imports of millions of
+// rows) without holding on to every error. It groups errors by [Fingerprint], and keeps a count and
+// a bounded sample of representative errors for each group. Create it with [NewStreamCollector].
+//
+// A StreamCollector is safe for concurrent use, so it can be shared by parallel workers.
+type StreamCollector struct {
+	message    string
+	maxSamples int
+
+	lock   sync.Mutex
+	groups map[string]*errorGroup
+	// Fingerprints in the order they were first seen, for stable output.
+	fingerprints []string
+	total        int
+}
+
+type errorGroup struct {
+	count   int
+	samples []error
+}
+
+// Log attribute keys used by StreamCollector.
+const (
+	ErrorCountKey  = "error_count"
+	FingerprintKey = "fingerprint"
+)
+
+// NewStreamCollector creates a [StreamCollector] that creates an error with the given message, and
+// keeps up to maxSamples errors for each group of similar errors (at least 1).
+func NewStreamCollector(message string, maxSamples int) *StreamCollector {
+	return &StreamCollector{
+		message:    message,
+		maxSamples: max(maxSamples, 1),
+		groups:     make(map[string]*errorGroup),
+	}
+}
+
+// Add records the given error. Nil errors are ignored.
+func (collector *StreamCollector) Add(err error) {
+	if err == nil {
+		return
+	}
+	fingerprint := Fingerprint(err) // Computed outside the lock, since it walks the whole error
+
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	collector.total++
+	group, ok := collector.groups[fingerprint]
+	if !ok {
+		group = &errorGroup{}
+		collector.groups[fingerprint] = group
+		collector.fingerprints = append(collector.fingerprints, fingerprint)
+	}
+	group.count++
+	if len(group.samples) < collector.maxSamples {
+		group.samples = append(group.samples, err)
+	}
+}
+
+// Count returns the total number of errors added to the collector.
+func (collector *StreamCollector) Count() int {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	return collector.total
+}
+
+// Err returns a summary of the errors added so far, or nil if none were added. Groups are listed in
+// the order they were first seen. Groups with a single error show the error itself, while larger
+// groups show their count and sampled errors:
+//
+//	collector := wrap.NewStreamCollector("import failed", 2)
+//	for row := 1; row <= 1000; row++ {
+//		collector.Add(wrap.Errorf(errors.New("invalid email"), "row %d failed", row))
+//	}
+//	collector.Add(errors.New("connection reset"))
+//	fmt.Println(collector.Err())
+//	// import failed
+//	// - 1000 similar errors (2 shown)
+//	//   - row 1 failed
+//	//     - invalid email
+//	//   - row 2 failed
+//	//     - invalid email
+//	// - connection reset
+//
+// The returned error has the total number of errors as a log attribute with the key
+// [ErrorCountKey], and each group of similar errors has its fingerprint with the key
+// [FingerprintKey].
+func (collector *StreamCollector) Err() error {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	if collector.total == 0 {
+		return nil
+	}
+
+	summaries := make([]error, 0, len(collector.fingerprints))
+	for _, fingerprint := range collector.fingerprints {
+		group := collector.groups[fingerprint]
+		if group.count == 1 {
+			summaries = append(summaries, group.samples[0])
+			continue
+		}
+
+		var message string
+		if group.count > len(group.samples) {
+			message = fmt.Sprintf("%d similar errors (%d shown)", group.count, len(group.samples))
+		} else {
+			message = fmt.Sprintf("%d similar errors", group.count)
+		}
+		summary := &wrappedErrors{message: message, wrapped: group.samples}
+		summaries = append(summaries, AddAttrs(summary, FingerprintKey, fingerprint))
+	}
+
+	return AddAttrs(Errors(collector.message, summaries...), ErrorCountKey, collector.total)
+}
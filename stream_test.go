@@ -0,0 +1,65 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestStreamCollector(t *testing.T) {
+	collector := wrap.NewStreamCollector("import failed", 2)
+	if collector.Err() != nil {
+		t.Fatal("expected nil error from empty collector")
+	}
+
+	invalidEmail := errors.New("invalid email")
+	for row := 1; row <= 1000; row++ {
+		collector.Add(wrap.Errorf(invalidEmail, "row %d failed", row))
+	}
+	collector.Add(errors.New("connection reset"))
+	collector.Add(nil)
+
+	expected := `import failed
+- 1000 similar errors (2 shown)
+  - row 1 failed
+    - invalid email
+  - row 2 failed
+    - invalid email
+- connection reset`
+
+	err := collector.Err()
+	assertEqualErrorStrings(t, err, expected)
+	assertEqualAttrs(t, err, []slog.Attr{slog.Int("error_count", 1001)})
+
+	if collector.Count() != 1001 {
+		t.Errorf("expected count 1001, got %d", collector.Count())
+	}
+	if !errors.Is(err, invalidEmail) {
+		t.Error("expected errors.Is to return true for sampled error")
+	}
+}
+
+func TestStreamCollectorConcurrent(t *testing.T) {
+	collector := wrap.NewStreamCollector("batch failed", 1)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				collector.Add(errors.New("timeout"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := `batch failed
+- 800 similar errors (1 shown)
+- timeout`
+
+	assertEqualErrorStrings(t, collector.Err(), expected)
+}
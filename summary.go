@@ -0,0 +1,90 @@
+package wrap
+
+// Summary counts the individual failures in an aggregated error tree, as returned by [Summarize].
+type Summary struct {
+	// Total is the number of failures in the tree.
+	Total int `json:"total"`
+	// ByCode is the number of failures per error code (see [CodeOf]). Failures without a code are
+	// not included.
+	ByCode map[string]int `json:"by_code"`
+	// ByFingerprint is the number of failures per [Fingerprint], for grouping similar failures
+	// that don't have a code.
+	ByFingerprint map[string]int `json:"by_fingerprint"`
+	// Retryable is the number of failures that are retryable (see [IsRetryable]).
+	Retryable int `json:"retryable"`
+}
+
+// Summarize counts the failures in the given error tree by code and fingerprint, for reporting
+// aggregated errors programmatically (e.g. "12 validation failures, 3 timeouts" in the response of
+// a batch job, or in a multi-status HTTP response).
+//
+// The failures of a tree are the branches of its multi-errors (such as the ones created by
+// [Errors]), with nested multi-errors flattened. An error without multi-errors counts as a single
+// failure. A failure without a code of its own gets the code of the closest layer above it that
+// has one, if any.
+//
+// Example:
+//
+//	invalid := errors.New("invalid email")
+//	err := wrap.Errors(
+//		"import failed",
+//		wrap.ErrorWithCode(invalid, "VALIDATION", "row 1 failed"),
+//		wrap.ErrorWithCode(invalid, "VALIDATION", "row 2 failed"),
+//		wrap.ErrorWithCode(errors.New("i/o timeout"), "TIMEOUT", "row 3 failed"),
+//	)
+//	fmt.Println(wrap.Summarize(err).ByCode)
+//	// map[TIMEOUT:1 VALIDATION:2]
+//
+// If the given error is nil, Summarize returns a summary with no failures.
+func Summarize(err error) Summary {
+	summary := Summary{ByCode: make(map[string]int), ByFingerprint: make(map[string]int)}
+	if err != nil {
+		summary.addFailures(err, "")
+	}
+	return summary
+}
+
+func (summary *Summary) addFailures(err error, inheritedCode string) {
+	multiErr, code, isMulti := nextMultiError(err)
+	if code != "" {
+		inheritedCode = code
+	}
+
+	if isMulti {
+		for _, wrappedErr := range multiErr.Unwrap() {
+			if wrappedErr != nil {
+				summary.addFailures(wrappedErr, inheritedCode)
+			}
+		}
+		return
+	}
+
+	summary.Total++
+	if inheritedCode != "" {
+		summary.ByCode[inheritedCode]++
+	}
+	summary.ByFingerprint[Fingerprint(err)]++
+	if IsRetryable(err) {
+		summary.Retryable++
+	}
+}
+
+// Returns the first multi-error in the given error's chain of single-wrapping layers, if any, and
+// the outermost code in the layers before it (or in the whole chain, if there is no multi-error).
+func nextMultiError(err error) (multiErr interface{ Unwrap() []error }, code string, ok bool) {
+	for err != nil {
+		if codedErr, isCoded := err.(codedError); isCoded && code == "" {
+			code, _ = codedErr.errorCode()
+		}
+
+		switch unwrappable := err.(type) {
+		case interface{ Unwrap() []error }:
+			return unwrappable, code, true
+		case interface{ Unwrap() error }:
+			err = unwrappable.Unwrap()
+		default:
+			return nil, code, false
+		}
+	}
+	return nil, code, false
+}
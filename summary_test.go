@@ -0,0 +1,59 @@
+package wrap_test
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestSummarize(t *testing.T) {
+	invalid := errors.New("invalid email")
+	timeouts := wrap.ErrorWithCode(
+		wrap.Errors(
+			"retries exhausted",
+			wrap.Retryable(errors.New("i/o timeout"), "attempt 1 failed"),
+			wrap.Retryable(errors.New("i/o timeout"), "attempt 1 failed"),
+		),
+		"TIMEOUT",
+		"row 3 failed",
+	)
+	err := wrap.Error(
+		wrap.Errors(
+			"import failed",
+			wrap.ErrorWithCode(wrap.Errorf(invalid, "row %d failed", 1), "VALIDATION", "bad row"),
+			wrap.ErrorWithCode(wrap.Errorf(invalid, "row %d failed", 2), "VALIDATION", "bad row"),
+			timeouts,
+			errors.New("unknown failure"),
+		),
+		"batch job failed",
+	)
+
+	summary := wrap.Summarize(err)
+
+	if summary.Total != 5 {
+		t.Errorf("expected 5 failures, got %d", summary.Total)
+	}
+	expected := map[string]int{"VALIDATION": 2, "TIMEOUT": 2}
+	if !maps.Equal(summary.ByCode, expected) {
+		t.Errorf("unexpected counts by code; got %v, want %v", summary.ByCode, expected)
+	}
+	if len(summary.ByFingerprint) != 3 {
+		t.Errorf("expected 3 distinct fingerprints, got %v", summary.ByFingerprint)
+	}
+	if summary.Retryable != 2 {
+		t.Errorf("expected 2 retryable failures, got %d", summary.Retryable)
+	}
+}
+
+func TestSummarizeSingleError(t *testing.T) {
+	summary := wrap.Summarize(wrap.ErrorWithCode(errors.New("error"), "CODE", "wrapped error"))
+	if summary.Total != 1 || summary.ByCode["CODE"] != 1 {
+		t.Errorf("expected single failure with code, got %+v", summary)
+	}
+
+	if summary := wrap.Summarize(nil); summary.Total != 0 {
+		t.Errorf("expected no failures for nil error, got %+v", summary)
+	}
+}
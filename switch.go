@@ -0,0 +1,47 @@
+package wrap
+
+// Switch dispatches the given error to the handler of the first case that matches it, making error
+// routing in handlers declarative instead of a chain of if/else checks. Cases are checked in order,
+// and a [Default] case matches any error. Switch returns true if a case handled the error. If the
+// error is nil, no handler is called.
+//
+// Example:
+//
+//	wrap.Switch(
+//		err,
+//		wrap.Case(isNotFound, func(err error) { respond(w, http.StatusNotFound, err) }),
+//		wrap.Case(wrap.IsCircuitOpen, func(err error) { respond(w, http.StatusServiceUnavailable, err) }),
+//		wrap.Default(func(err error) { respond(w, http.StatusInternalServerError, err) }),
+//	)
+func Switch(err error, cases ...SwitchCase) (handled bool) {
+	if err == nil {
+		return false
+	}
+
+	for _, switchCase := range cases {
+		if switchCase.match == nil || switchCase.match(err) {
+			switchCase.handle(err)
+			return true
+		}
+	}
+
+	return false
+}
+
+// SwitchCase is a case in [Switch], created with [Case] or [Default].
+type SwitchCase struct {
+	match  func(err error) bool
+	handle func(err error)
+}
+
+// Case creates a [Switch] case that calls handle if the match function returns true for the error.
+// Predicates such as [IsCircuitOpen] can be used as match functions, as well as closures around
+// [errors.Is] and [errors.As].
+func Case(match func(err error) bool, handle func(err error)) SwitchCase {
+	return SwitchCase{match: match, handle: handle}
+}
+
+// Default creates a [Switch] case that matches any error. It should be the last case.
+func Default(handle func(err error)) SwitchCase {
+	return SwitchCase{match: nil, handle: handle}
+}
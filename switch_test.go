@@ -0,0 +1,59 @@
+package wrap_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestSwitch(t *testing.T) {
+	isNotExist := func(err error) bool { return errors.Is(err, fs.ErrNotExist) }
+
+	testCases := []struct {
+		err      error
+		expected string
+	}{
+		{wrap.Error(fs.ErrNotExist, "failed to open file"), "not found"},
+		{wrap.CircuitOpen("request rejected"), "circuit open"},
+		{errors.New("unexpected error"), "default"},
+	}
+
+	for _, testCase := range testCases {
+		var handledBy string
+		handled := wrap.Switch(
+			testCase.err,
+			wrap.Case(isNotExist, func(error) { handledBy = "not found" }),
+			wrap.Case(wrap.IsCircuitOpen, func(error) { handledBy = "circuit open" }),
+			wrap.Default(func(error) { handledBy = "default" }),
+		)
+
+		if !handled {
+			t.Errorf("expected Switch to handle error '%v'", testCase.err)
+		}
+		if handledBy != testCase.expected {
+			t.Errorf(
+				"unexpected case for error '%v'; got %s, want %s",
+				testCase.err,
+				handledBy,
+				testCase.expected,
+			)
+		}
+	}
+}
+
+func TestSwitchNoMatch(t *testing.T) {
+	handled := wrap.Switch(
+		errors.New("error"),
+		wrap.Case(wrap.IsCircuitOpen, func(error) { t.Error("unexpected call to handler") }),
+	)
+	if handled {
+		t.Error("expected Switch to return false when no case matches")
+	}
+
+	handled = wrap.Switch(nil, wrap.Default(func(error) { t.Error("unexpected call to handler") }))
+	if handled {
+		t.Error("expected Switch to return false for nil error")
+	}
+}
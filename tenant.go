@@ -0,0 +1,53 @@
+package wrap
+
+// DefaultTenantKey is the attribute key used by [TenantCheck] when no key is configured.
+const DefaultTenantKey = "tenant"
+
+// TenantCheck is an opt-in check for multi-tenant systems, verifying that errors carry a tenant
+// attribute before they cross a reporting boundary (such as being logged or sent to an error
+// tracker). Errors without tenant attribution are hard to triage, so the check lets you find the
+// code paths that produce them.
+//
+// Attributes are read from the LogAttrs method of the error and all errors it wraps, which is the
+// method that logging libraries such as [hermannm.dev/devlog/log] use for structured errors.
+//
+// Example:
+//
+//	check := wrap.TenantCheck{
+//		Key: "tenant_id",
+//		OnMissing: func(err error) {
+//			slog.Warn("error is missing tenant attribute", "error", err)
+//		},
+//	}
+//	check.Verify(err)
+type TenantCheck struct {
+	// Key is the attribute key that identifies the tenant. If empty, [DefaultTenantKey] is used.
+	Key string
+
+	// OnMissing, if set, is called with every error that fails the check.
+	OnMissing func(err error)
+}
+
+// Verify checks that the given error or any error it wraps has a tenant attribute. If not, it calls
+// the check's OnMissing function and returns false. A nil error always passes the check.
+func (check TenantCheck) Verify(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	key := check.Key
+	if key == "" {
+		key = DefaultTenantKey
+	}
+
+	for _, attr := range Attrs(err) {
+		if attr.Key == key {
+			return true
+		}
+	}
+
+	if check.OnMissing != nil {
+		check.OnMissing(err)
+	}
+	return false
+}
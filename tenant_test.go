@@ -0,0 +1,69 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+type errorWithAttrs struct {
+	message string
+	attrs   []slog.Attr
+}
+
+func (err *errorWithAttrs) Error() string {
+	return err.message
+}
+
+func (err *errorWithAttrs) LogAttrs() []slog.Attr {
+	return err.attrs
+}
+
+func TestTenantCheck(t *testing.T) {
+	err := &errorWithAttrs{
+		message: "quota exceeded",
+		attrs:   []slog.Attr{slog.String("tenant_id", "acme")},
+	}
+	wrapped := wrap.Errors(
+		"batch failed",
+		errors.New("other error"),
+		wrap.Error(err, "upload failed"),
+	)
+
+	var missing []error
+	check := wrap.TenantCheck{
+		Key:       "tenant_id",
+		OnMissing: func(err error) { missing = append(missing, err) },
+	}
+
+	if !check.Verify(wrapped) {
+		t.Error("expected tenant check to pass for error with tenant attribute")
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected OnMissing not to be called, but got %d calls", len(missing))
+	}
+}
+
+func TestTenantCheckMissing(t *testing.T) {
+	err := &errorWithAttrs{
+		message: "quota exceeded",
+		attrs:   []slog.Attr{slog.String("user_id", "123")},
+	}
+	wrapped := wrap.Error(err, "upload failed")
+
+	var missing []error
+	check := wrap.TenantCheck{OnMissing: func(err error) { missing = append(missing, err) }}
+
+	if check.Verify(wrapped) {
+		t.Error("expected tenant check to fail for error without tenant attribute")
+	}
+	if len(missing) != 1 || missing[0] != wrapped {
+		t.Errorf("expected OnMissing to be called once with the checked error, got %v", missing)
+	}
+
+	if !check.Verify(nil) {
+		t.Error("expected tenant check to pass for nil error")
+	}
+}
@@ -0,0 +1,179 @@
+package wrap
+
+import (
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var recordCallers atomic.Bool
+
+// SetRecordCallers sets whether the wrapping functions in this package should record the source
+// location where each layer was wrapped, for use with [Trail]. The package path of the code that
+// wrapped each layer is also included in [Layer.Package] and the output of [FormatDOT], so that it
+// is clear which component added which context. Recording a caller costs a short stack walk per
+// wrap (the frames are only resolved when read), so it is disabled by default.
+func SetRecordCallers(record bool) {
+	recordCallers.Store(record)
+}
+
+// TrailEntry is a layer in an error's trail of source locations (see [Trail]).
+type TrailEntry struct {
+	// Message is the wrapping message of the layer.
+	Message string `json:"message"`
+	// Package is the import path of the package where the layer was wrapped, e.g.
+	// "example.com/billing/invoice".
+	Package string `json:"package"`
+	// Function, File and Line identify the call site where the layer was wrapped.
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	// Count is the number of consecutive layers that were wrapped at this call site (e.g. in a
+	// retry loop), which are collapsed into one entry.
+	Count int `json:"count"`
+}
+
+// Trail returns the source locations where the layers of the given error were wrapped, outermost
+// first, if recorded with [SetRecordCallers]. Layers without a recorded caller are left out.
+//
+// Consecutive layers wrapped at the same call site are collapsed into one entry, with Count set to
+// the number of layers. This keeps trails readable when errors are wrapped in a loop:
+//
+//	wrap.SetRecordCallers(true)
+//	for attempt := 1; attempt <= 3; attempt++ {
+//		err = wrap.Errorf(err, "attempt %d failed", attempt)
+//	}
+//	fmt.Println(wrap.Trail(err)[0].Count)
+//	// 3
+//
+// For multi-errors, the trail ends at the layer that wraps multiple errors, since the trail of
+// each branch is different. Errors created by [hermannm.dev/wrap/ctxwrap] don't record callers.
+func Trail(err error) []TrailEntry {
+	var trail []TrailEntry
+	for err != nil {
+		if withCaller, ok := err.(interface{ callSite() *callSite }); ok {
+			if site := withCaller.callSite(); site.hasCaller() {
+				trail = appendTrailEntry(trail, err, site.resolve())
+			}
+		}
+
+		unwrappable, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = unwrappable.Unwrap()
+	}
+	return trail
+}
+
+func appendTrailEntry(trail []TrailEntry, err error, frame runtime.Frame) []TrailEntry {
+	if len(trail) != 0 {
+		last := &trail[len(trail)-1]
+		if last.File == frame.File && last.Line == frame.Line {
+			last.Count++
+			return trail
+		}
+	}
+
+	var message string
+	switch err := err.(type) {
+	case interface{ WrappingMessage() string }:
+		message = err.WrappingMessage()
+	case *leafError:
+		message = err.message
+	}
+
+	return append(trail, TrailEntry{
+		Message:  message,
+		Package:  functionPackage(frame.Function),
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+		Count:    1,
+	})
+}
+
+// Where and when an error was wrapped: the program counters of the call stack (if recorded with
+// SetRecordCallers), and the time (if recorded with SetRecordCreationTimes). Stored behind a
+// pointer on error values, so that they stay comparable.
+type callSite struct {
+	programCounters []uintptr
+	createdAt       time.Time
+}
+
+// Maximum number of frames recorded for a call site. Only the first frame outside this module is
+// used, so this just needs to cover the internal call depth (e.g. Defer calling Error).
+const maxCallSiteDepth = 8
+
+// Returns the call site of the caller of the exported wrapping function, or nil if recording both
+// callers and creation times is disabled.
+func recordCaller() *callSite {
+	recordStack, recordTime := recordCallers.Load(), recordCreationTimes.Load()
+	if !recordStack && !recordTime {
+		return nil
+	}
+
+	var site callSite
+	if recordStack {
+		programCounters := make([]uintptr, maxCallSiteDepth)
+		// Skips runtime.Callers and recordCaller itself
+		count := runtime.Callers(2, programCounters)
+		site.programCounters = programCounters[:count]
+	}
+	if recordTime {
+		site.createdAt = time.Now()
+	}
+	return &site
+}
+
+// Returns whether the caller was recorded for the call site, which may be nil.
+func (site *callSite) hasCaller() bool {
+	return site != nil && len(site.programCounters) != 0
+}
+
+// Resolves the first frame outside of this module's packages, which is where the user called a
+// wrapping function (possibly through a helper such as Defer or ErrorIf).
+func (site *callSite) resolve() runtime.Frame {
+	frames := runtime.CallersFrames(site.programCounters)
+	for {
+		frame, more := frames.Next()
+		if !isModuleFunction(frame.Function) || !more {
+			return frame
+		}
+	}
+}
+
+// Returns the package path of the given package path-qualified function name, e.g.
+// "example.com/billing/invoice" for "example.com/billing/invoice.(*Service).Create".
+func functionPackage(function string) string {
+	lastSlash := strings.LastIndexByte(function, '/')
+	if dot := strings.IndexByte(function[lastSlash+1:], '.'); dot != -1 {
+		return function[:lastSlash+1+dot]
+	}
+	return function
+}
+
+// Returns the package path where the given error was wrapped, if its caller was recorded.
+func errorPackage(err error) string {
+	if withCaller, ok := err.(interface{ callSite() *callSite }); ok {
+		if site := withCaller.callSite(); site.hasCaller() {
+			return functionPackage(site.resolve().Function)
+		}
+	}
+	return ""
+}
+
+func isModuleFunction(function string) bool {
+	const modulePath = "hermannm.dev/wrap"
+	return strings.HasPrefix(function, modulePath+".") ||
+		strings.HasPrefix(function, modulePath+"/")
+}
+
+func (err wrappedError) callSite() *callSite {
+	return err.caller
+}
+
+func (err wrappedErrors) callSite() *callSite {
+	return err.caller
+}
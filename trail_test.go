@@ -0,0 +1,84 @@
+package wrap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestTrail(t *testing.T) {
+	wrap.SetRecordCallers(true)
+	defer wrap.SetRecordCallers(false)
+
+	err := errors.New("connection refused")
+	for attempt := 1; attempt <= 3; attempt++ {
+		err = wrap.Errorf(err, "attempt %d failed", attempt)
+	}
+	err = wrap.Error(err, "failed to fetch user")
+
+	trail := wrap.Trail(err)
+	if len(trail) != 2 {
+		t.Fatalf("expected 2 trail entries, got %d: %+v", len(trail), trail)
+	}
+
+	if trail[0].Message != "failed to fetch user" || trail[0].Count != 1 {
+		t.Errorf("unexpected first trail entry: %+v", trail[0])
+	}
+	if trail[1].Message != "attempt 3 failed" || trail[1].Count != 3 {
+		t.Errorf("expected retry layers to be collapsed, got %+v", trail[1])
+	}
+	for _, entry := range trail {
+		if !strings.HasSuffix(entry.Function, "TestTrail") ||
+			!strings.HasSuffix(entry.File, "trail_test.go") {
+			t.Errorf("expected trail entry to point to the test, got %+v", entry)
+		}
+	}
+}
+
+func TestTrailThroughHelpers(t *testing.T) {
+	wrap.SetRecordCallers(true)
+	defer wrap.SetRecordCallers(false)
+
+	err := wrap.ErrorIf(errors.New("error"), "wrapped error")
+
+	trail := wrap.Trail(err)
+	if len(trail) != 1 || !strings.HasSuffix(trail[0].Function, "TestTrailThroughHelpers") {
+		t.Errorf("expected trail to point to the caller of ErrorIf, got %+v", trail)
+	}
+}
+
+func TestTrailDisabled(t *testing.T) {
+	err := wrap.Error(errors.New("error"), "wrapped error")
+
+	if trail := wrap.Trail(err); len(trail) != 0 {
+		t.Errorf("expected empty trail when callers are not recorded, got %+v", trail)
+	}
+}
+
+func TestLayerPackages(t *testing.T) {
+	wrap.SetRecordCallers(true)
+	defer wrap.SetRecordCallers(false)
+
+	inner := wrap.Errors("validation failed", errors.New("invalid email"))
+	err := wrap.Error(inner, "request failed")
+
+	const expectedPackage = "hermannm.dev/wrap_test"
+	if trail := wrap.Trail(err); trail[0].Package != expectedPackage {
+		t.Errorf("unexpected trail package %q", trail[0].Package)
+	}
+
+	layers := wrap.Flatten(err)
+	if layers[0].Package != expectedPackage || layers[1].Package != expectedPackage {
+		t.Errorf("expected wrapping layers to have package, got %+v", layers)
+	}
+	if layers[2].Package != "" {
+		t.Errorf("expected leaf layer without package, got %q", layers[2].Package)
+	}
+
+	expectedLabel := `n0 [label="request failed\n(hermannm.dev/wrap_test)"];`
+	if dot := wrap.FormatDOT(err); !strings.Contains(dot, expectedLabel) {
+		t.Errorf("expected DOT output to contain %s, got:\n%s", expectedLabel, dot)
+	}
+}
@@ -0,0 +1,145 @@
+package wrap
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// UpspinError holds the fields of an error in the style of upspin.io/errors, where errors are
+// constructed from an operation name, an error kind, a user name and an underlying error. Use
+// [FromUpspin] to convert such errors, for codebases migrating from upspin-style error packages.
+type UpspinError struct {
+	// Op is the operation being performed, e.g. "client.Lookup".
+	Op string
+	// Kind is the class of error, e.g. "permission denied" or "item does not exist".
+	Kind string
+	// User is the name of the user attempting the operation.
+	User string
+	// Err is the underlying error that triggered this one, if any.
+	Err error
+}
+
+// FromUpspin converts an upspin-style error to a wrapped error, so that it is formatted and matched
+// consistently with other errors from this package:
+//   - Op becomes the wrapping message (or Kind, if Op is empty)
+//   - Op, Kind and User are attached as the log attributes "op", "kind" and "user" (omitting empty
+//     fields)
+//   - Kind becomes the error code (see [CodeOf] and [CodeKey]), in upper snake case (e.g.
+//     "permission denied" becomes "PERMISSION_DENIED"), so that converted errors are classified
+//     like other coded errors
+//   - Err becomes the wrapped error. If Err is nil, an error with Kind as its message is wrapped
+//     instead, or the Kind is used as the message directly if Op is empty.
+//
+// Example:
+//
+//	err := wrap.FromUpspin(wrap.UpspinError{
+//		Op:   "client.Lookup",
+//		Kind: "item does not exist",
+//		User: "ann@example.com",
+//		Err:  errors.New("no such file"),
+//	})
+//	fmt.Println(err)
+//	// client.Lookup
+//	// - no such file
+//
+// Use [UpspinKind] to match errors by kind.
+func FromUpspin(upspinErr UpspinError) error {
+	var attrs []any
+	if upspinErr.Op != "" {
+		attrs = append(attrs, "op", upspinErr.Op)
+	}
+	if upspinErr.Kind != "" {
+		attrs = append(attrs, "kind", upspinErr.Kind)
+	}
+	if upspinErr.User != "" {
+		attrs = append(attrs, "user", upspinErr.User)
+	}
+
+	message := upspinErr.Op
+	wrapped := upspinErr.Err
+	if message == "" {
+		message = upspinErr.Kind
+	} else if wrapped == nil && upspinErr.Kind != "" {
+		wrapped = errors.New(upspinErr.Kind)
+	}
+
+	var err error
+	if wrapped == nil {
+		err = NewErrorWithAttrs(message, attrs...)
+	} else {
+		err = ErrorWithAttrs(wrapped, message, attrs...)
+	}
+
+	if upspinErr.Kind == "" {
+		return err
+	}
+	return withNetError(&upspinKindError{
+		wrapped: err,
+		kind:    upspinErr.Kind,
+		code:    upspinKindCode(upspinErr.Kind),
+	})
+}
+
+// Converts an upspin error kind to an error code in upper snake case, replacing each run of
+// characters other than letters and digits with an underscore (e.g. "I/O error" becomes
+// "I_O_ERROR").
+func upspinKindCode(kind string) string {
+	var code strings.Builder
+	separate := false
+	for _, char := range kind {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			separate = code.Len() != 0
+			continue
+		}
+		if separate {
+			code.WriteByte('_')
+			separate = false
+		}
+		code.WriteRune(unicode.ToUpper(char))
+	}
+	return code.String()
+}
+
+// UpspinKind returns the kind of the outermost error in the given error's chain that was converted
+// with [FromUpspin] with a non-empty Kind, if any.
+func UpspinKind(err error) (kind string, ok bool) {
+	var kindErr *upspinKindError
+	if errors.As(err, &kindErr) {
+		return kindErr.kind, true
+	}
+	return "", false
+}
+
+type upspinKindError struct {
+	wrapped error
+	kind    string
+	code    string
+}
+
+func (err *upspinKindError) Error() string {
+	return err.wrapped.Error()
+}
+
+// Unwrap matches the signature for wrapped errors expected by the [errors] package.
+func (err *upspinKindError) Unwrap() error {
+	return err.wrapped
+}
+
+func (err *upspinKindError) unwrapMarker() error {
+	return err.wrapped
+}
+
+func (err *upspinKindError) errorCode() (code string, ok bool) {
+	return err.code, err.code != ""
+}
+
+// LogAttrs returns the error code converted from the kind as a structured log attribute, for
+// logging libraries that look for this method (such as [hermannm.dev/devlog/log]).
+func (err *upspinKindError) LogAttrs() []slog.Attr {
+	if err.code == "" {
+		return nil
+	}
+	return []slog.Attr{slog.String(CodeKey, err.code)}
+}
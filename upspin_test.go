@@ -0,0 +1,90 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestFromUpspin(t *testing.T) {
+	cause := errors.New("no such file")
+	err := wrap.FromUpspin(wrap.UpspinError{
+		Op:   "client.Lookup",
+		Kind: "item does not exist",
+		User: "ann@example.com",
+		Err:  cause,
+	})
+	wrapped := wrap.Error(err, "failed to read config")
+
+	expected := `failed to read config
+- client.Lookup
+- no such file`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+	assertEqualAttrSlices(t, wrap.Attrs(wrapped), []slog.Attr{
+		slog.String("code", "ITEM_DOES_NOT_EXIST"),
+		slog.String("op", "client.Lookup"),
+		slog.String("kind", "item does not exist"),
+		slog.String("user", "ann@example.com"),
+	})
+
+	kind, ok := wrap.UpspinKind(wrapped)
+	if !ok || kind != "item does not exist" {
+		t.Errorf("unexpected kind; got '%s' (found: %t), want 'item does not exist'", kind, ok)
+	}
+	code, ok := wrap.CodeOf(wrapped)
+	if !ok || code != "ITEM_DOES_NOT_EXIST" {
+		t.Errorf("unexpected code; got '%s' (found: %t), want 'ITEM_DOES_NOT_EXIST'", code, ok)
+	}
+
+	if !errors.Is(wrapped, cause) {
+		t.Error("expected errors.Is to return true for converted upspin error")
+	}
+}
+
+func TestFromUpspinWithoutErr(t *testing.T) {
+	err := wrap.FromUpspin(wrap.UpspinError{Op: "client.Put", Kind: "permission denied"})
+
+	expected := `client.Put
+- permission denied`
+
+	assertEqualErrorStrings(t, err, expected)
+
+	err = wrap.FromUpspin(wrap.UpspinError{Kind: "permission denied", User: "ann@example.com"})
+
+	assertEqualErrorStrings(t, err, "permission denied")
+	assertEqualAttrSlices(t, wrap.Attrs(err), []slog.Attr{
+		slog.String("code", "PERMISSION_DENIED"),
+		slog.String("kind", "permission denied"),
+		slog.String("user", "ann@example.com"),
+	})
+}
+
+func TestUpspinKindCode(t *testing.T) {
+	for kind, expected := range map[string]string{
+		"permission denied":    "PERMISSION_DENIED",
+		"I/O error":            "I_O_ERROR",
+		" item already exists": "ITEM_ALREADY_EXISTS",
+		"...":                  "",
+	} {
+		err := wrap.FromUpspin(wrap.UpspinError{Op: "client.Put", Kind: kind})
+		code, ok := wrap.CodeOf(err)
+		if code != expected || ok != (expected != "") {
+			t.Errorf(
+				"unexpected code for kind '%s'; got '%s' (found: %t), want '%s'",
+				kind,
+				code,
+				ok,
+				expected,
+			)
+		}
+	}
+}
+
+func TestNotUpspin(t *testing.T) {
+	if _, ok := wrap.UpspinKind(errors.New("error")); ok {
+		t.Error("expected UpspinKind to return false for error not converted from upspin")
+	}
+}
@@ -0,0 +1,66 @@
+package wrap
+
+// Value captures the results of a call returning a value and an error, so that the error can be
+// wrapped in a single expression with [ValueResult.Error] or [ValueResult.Errorf]:
+//
+//	func getUser(id int) (User, error) {
+//		return wrap.Value(db.GetUser(id)).Errorf("failed to get user with ID %d", id)
+//	}
+//
+// This replaces the boilerplate of checking the error and returning a zero value. Go doesn't allow
+// passing the results of a multi-value call together with other arguments, which is why the message
+// is given in a separate method call.
+func Value[T any](value T, err error) ValueResult[T] {
+	return ValueResult[T]{value: value, err: err}
+}
+
+// ValueResult holds the results of a call, captured by [Value].
+type ValueResult[T any] struct {
+	value T
+	err   error
+}
+
+// Error returns the captured value and error, wrapping the error with the given message (like
+// [Error]) if it is non-nil. The value is returned as-is, also when there is an error.
+func (result ValueResult[T]) Error(message string) (T, error) {
+	return result.value, ErrorIf(result.err, message)
+}
+
+// Errorf returns the captured value and error, wrapping the error with the given formatted message
+// (like [Errorf]) if it is non-nil. The value is returned as-is, also when there is an error.
+func (result ValueResult[T]) Errorf(messageFormat string, formatArgs ...any) (T, error) {
+	return result.value, ErrorfIf(result.err, messageFormat, formatArgs...)
+}
+
+// Value2 works like [Value], but for calls returning two values and an error.
+//
+// Example:
+//
+//	func readConfig(path string) ([]byte, os.FileInfo, error) {
+//		return wrap.Value2(readFileWithInfo(path)).Error("failed to read config")
+//	}
+func Value2[T1 any, T2 any](value1 T1, value2 T2, err error) Value2Result[T1, T2] {
+	return Value2Result[T1, T2]{value1: value1, value2: value2, err: err}
+}
+
+// Value2Result holds the results of a call, captured by [Value2].
+type Value2Result[T1 any, T2 any] struct {
+	value1 T1
+	value2 T2
+	err    error
+}
+
+// Error returns the captured values and error, wrapping the error with the given message (like
+// [Error]) if it is non-nil. The values are returned as-is, also when there is an error.
+func (result Value2Result[T1, T2]) Error(message string) (T1, T2, error) {
+	return result.value1, result.value2, ErrorIf(result.err, message)
+}
+
+// Errorf returns the captured values and error, wrapping the error with the given formatted message
+// (like [Errorf]) if it is non-nil. The values are returned as-is, also when there is an error.
+func (result Value2Result[T1, T2]) Errorf(
+	messageFormat string,
+	formatArgs ...any,
+) (T1, T2, error) {
+	return result.value1, result.value2, ErrorfIf(result.err, messageFormat, formatArgs...)
+}
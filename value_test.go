@@ -0,0 +1,48 @@
+package wrap_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestValue(t *testing.T) {
+	parse := func(input string) (int, error) {
+		return wrap.Value(strconv.Atoi(input)).Errorf("failed to parse '%s'", input)
+	}
+
+	value, err := parse("123")
+	if value != 123 || err != nil {
+		t.Errorf("unexpected result for valid input; got (%d, %v)", value, err)
+	}
+
+	_, err = parse("abc")
+	expected := `failed to parse 'abc'
+- strconv.Atoi: parsing "abc": invalid syntax`
+
+	assertEqualErrorStrings(t, err, expected)
+}
+
+func TestValue2(t *testing.T) {
+	split := func(input string) (string, string, error) {
+		for i := range input {
+			if input[i] == '=' {
+				return input[:i], input[i+1:], nil
+			}
+		}
+		return "", "", errors.New("missing '='")
+	}
+
+	key, value, err := wrap.Value2(split("key=value")).Error("invalid pair")
+	if key != "key" || value != "value" || err != nil {
+		t.Errorf("unexpected result for valid input; got (%s, %s, %v)", key, value, err)
+	}
+
+	_, _, err = wrap.Value2(split("key")).Error("invalid pair")
+	expected := `invalid pair
+- missing '='`
+
+	assertEqualErrorStrings(t, err, expected)
+}
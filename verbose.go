@@ -0,0 +1,35 @@
+package wrap
+
+import (
+	"context"
+)
+
+type verboseErrorsKey struct{}
+
+// WithVerboseErrors returns a copy of the given context that is flagged for verbose errors. Errors
+// created with a flagged context (by [E] or [Builder] with [WithContext], or by
+// [hermannm.dev/wrap/ctxwrap]) capture a stack trace, as if [WithStack] was given. This lets you
+// turn on expensive diagnostics for a single request (such as a canary or debug request), instead
+// of for all of them:
+//
+//	func debugMiddleware(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			if r.Header.Get("X-Debug-Errors") == "true" {
+//				r = r.WithContext(wrap.WithVerboseErrors(r.Context()))
+//			}
+//			next.ServeHTTP(w, r)
+//		})
+//	}
+func WithVerboseErrors(ctx context.Context) context.Context {
+	return context.WithValue(ctx, verboseErrorsKey{}, true)
+}
+
+// VerboseErrors returns whether the given context is flagged for verbose errors with
+// [WithVerboseErrors].
+func VerboseErrors(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	verbose, _ := ctx.Value(verboseErrorsKey{}).(bool)
+	return verbose
+}
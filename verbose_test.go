@@ -0,0 +1,36 @@
+package wrap_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestVerboseErrors(t *testing.T) {
+	ctx := context.Background()
+	if wrap.VerboseErrors(ctx) {
+		t.Error("expected context not to be flagged for verbose errors")
+	}
+
+	err := wrap.E(errors.New("error"), "wrapped error", wrap.WithContext(ctx))
+	if _, ok := wrap.Stack(err); ok {
+		t.Error("expected no stack trace for error with unflagged context")
+	}
+
+	ctx = wrap.WithVerboseErrors(ctx)
+	if !wrap.VerboseErrors(ctx) {
+		t.Error("expected context to be flagged for verbose errors")
+	}
+
+	err = wrap.E(errors.New("error"), "wrapped error", wrap.WithContext(ctx))
+	stack, ok := wrap.Stack(err)
+	if !ok {
+		t.Fatal("expected stack trace for error with verbose context")
+	}
+	if frames := stack.Frames(); !strings.HasSuffix(frames[0].Function, "TestVerboseErrors") {
+		t.Errorf("expected stack trace to start at the caller of E, got %s", frames[0].Function)
+	}
+}
@@ -0,0 +1,113 @@
+package wrap
+
+import (
+	"context"
+	"log/slog"
+
+	"hermannm.dev/wrap/errschema"
+)
+
+// Layer is a single layer of an error tree, as visited by [Walk] and returned by [Flatten].
+type Layer struct {
+	// Err is the error value of the layer.
+	Err error
+	// Message is the wrapping message of the layer, or the full error string if the layer is a leaf
+	// (i.e. an error that doesn't wrap other errors with a message).
+	Message string
+	// Attrs are the structured log attributes attached to this layer, not including attributes of
+	// the errors it wraps. Attributes from errors that only attach metadata to the layer (such as
+	// error codes) are included.
+	Attrs []slog.Attr
+	// Context is the context attached to the layer (e.g. by [hermannm.dev/wrap/ctxwrap]), or nil
+	// if there is none.
+	Context context.Context
+	// Children are the errors wrapped by this layer. It has one element for layers wrapping a
+	// single error, several for layers wrapping multiple errors, and none for leaves.
+	Children []error
+	// Depth is the number of layers above this one in the tree, starting at 0.
+	Depth int
+	// Package is the import path of the package where the layer was wrapped, if recorded with
+	// [SetRecordCallers], or "" otherwise.
+	Package string
+}
+
+// Walk calls the given function for each layer of the given error tree, depth-first, starting with
+// the outermost layer. If the function returns false, the walk stops. Layers are split the same
+// way as in the formatted error string, so errors that only attach metadata (such as stack traces
+// or codes) are merged into the layer they wrap.
+//
+// Use this to inspect the structure of an error programmatically (e.g. for building API responses
+// or metrics), instead of parsing the formatted error string.
+func Walk(err error, fn func(layer Layer) bool) {
+	walkLayer(err, 0, fn)
+}
+
+// Flatten returns the layers of the given error tree, in the same order as visited by [Walk].
+func Flatten(err error) []Layer {
+	var layers []Layer
+	Walk(err, func(layer Layer) bool {
+		layers = append(layers, layer)
+		return true
+	})
+	return layers
+}
+
+// Visits the layer of the given error and its children, returning false if the walk was stopped.
+func walkLayer(err error, depth int, fn func(layer Layer) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	layer := newLayer(err, depth)
+	if !fn(layer) {
+		return false
+	}
+	for _, child := range layer.Children {
+		if !walkLayer(child, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Creates the layer for the given non-nil error, merging any markers around it into the layer.
+func newLayer(err error, depth int) Layer {
+	layer := Layer{Depth: depth}
+	for {
+		if withAttrs, ok := err.(hasLogAttrs); ok {
+			layer.Attrs = append(layer.Attrs, withAttrs.LogAttrs()...)
+		}
+		if withContext, ok := err.(errschema.ErrorWithContext); ok &&
+			layer.Context == nil {
+			layer.Context = withContext.Context()
+		}
+
+		marker, ok := err.(markerError)
+		if !ok {
+			break
+		}
+		err = marker.unwrapMarker()
+	}
+	layer.Err = err
+	layer.Package = errorPackage(err)
+
+	switch err := err.(type) {
+	case wrappingError:
+		layer.Message = err.WrappingMessage()
+		if wrapped := err.Unwrap(); wrapped != nil {
+			layer.Children = []error{wrapped}
+		}
+	case wrappingErrors:
+		layer.Message = err.WrappingMessage()
+		layer.Children = err.Unwrap()
+	default:
+		if message, attrs, ok := formatLeaf(err); ok {
+			layer.Message = message
+			layer.Attrs = append(layer.Attrs, attrs...)
+		} else {
+			layer.Message = err.Error()
+		}
+	}
+
+	return layer
+}
@@ -0,0 +1,63 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestFlatten(t *testing.T) {
+	err := wrap.Errors(
+		"user creation failed",
+		wrap.ErrorWithCode(errors.New("username too long"), "INVALID_USERNAME", "invalid username"),
+		errors.New("invalid email"),
+	)
+	outer := wrap.ErrorWithAttrs(err, "failed to register new user", "user_id", 123)
+
+	layers := wrap.Flatten(outer)
+
+	expected := []struct {
+		message string
+		depth   int
+		attrs   []slog.Attr
+	}{
+		{"failed to register new user", 0, []slog.Attr{slog.Int("user_id", 123)}},
+		{"user creation failed", 1, nil},
+		{"invalid username", 2, []slog.Attr{slog.String("code", "INVALID_USERNAME")}},
+		{"username too long", 3, nil},
+		{"invalid email", 2, nil},
+	}
+
+	if len(layers) != len(expected) {
+		t.Fatalf("expected %d layers, got %d: %+v", len(expected), len(layers), layers)
+	}
+	for i, layer := range layers {
+		if layer.Message != expected[i].message || layer.Depth != expected[i].depth {
+			t.Errorf(
+				"unexpected layer at index %d; got (%q, depth %d), want (%q, depth %d)",
+				i, layer.Message, layer.Depth, expected[i].message, expected[i].depth,
+			)
+		}
+		assertEqualAttrSlices(t, layer.Attrs, expected[i].attrs)
+	}
+
+	if len(layers[1].Children) != 2 {
+		t.Errorf("expected multi-error layer to have 2 children, got %d", len(layers[1].Children))
+	}
+}
+
+func TestWalkStops(t *testing.T) {
+	err := wrap.Error(wrap.Error(errors.New("error"), "inner wrapped error"), "outer wrapped error")
+
+	var messages []string
+	wrap.Walk(err, func(layer wrap.Layer) bool {
+		messages = append(messages, layer.Message)
+		return len(messages) < 2
+	})
+
+	if len(messages) != 2 || messages[1] != "inner wrapped error" {
+		t.Errorf("expected walk to stop after second layer, got %v", messages)
+	}
+}
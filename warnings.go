@@ -0,0 +1,67 @@
+package wrap
+
+import (
+	"log/slog"
+)
+
+// Warnings collects non-fatal issues that occurred while producing an otherwise successful result,
+// such as skipped rows in an import. Unlike an error, Warnings does not signal failure, so it is
+// meant to be returned alongside a result rather than in place of it.
+//
+// Warnings are rendered with the same format as [Errors]:
+//
+//	var warnings wrap.Warnings
+//	warnings.Add(errors.New("row 3: missing email"))
+//	warnings.Add(wrap.Error(errors.New("invalid date"), "row 7: failed to parse birthday"))
+//	fmt.Println(warnings.Format("import completed with 2 warnings"))
+//	// import completed with 2 warnings
+//	// - row 3: missing email
+//	// - row 7: failed to parse birthday
+//	//   - invalid date
+//
+// The zero value is an empty list of warnings, ready to use. Warnings is not safe for concurrent
+// use.
+type Warnings struct {
+	warnings []error
+}
+
+// Add appends the given warning to the list. Nil warnings are ignored.
+func (warnings *Warnings) Add(warning error) {
+	if warning != nil {
+		warnings.warnings = append(warnings.warnings, warning)
+	}
+}
+
+// Len returns the number of warnings in the list.
+func (warnings *Warnings) Len() int {
+	return len(warnings.warnings)
+}
+
+// List returns the warnings in the order they were added.
+func (warnings *Warnings) List() []error {
+	return warnings.warnings
+}
+
+// Format renders the warnings as a list under the given message, in the same format as [Errors].
+func (warnings *Warnings) Format(message string) string {
+	var builder errorBuilder
+	builder.WriteString(message)
+	builder.writeErrorList(warnings.warnings, 1)
+	return builder.String()
+}
+
+// LogValue implements [slog.LogValuer], logging the number of warnings, their messages, and any log
+// attributes carried by the warnings.
+func (warnings *Warnings) LogValue() slog.Value {
+	messages := make([]string, 0, len(warnings.warnings))
+	var warningAttrs []slog.Attr
+	for _, warning := range warnings.warnings {
+		messages = append(messages, warning.Error())
+		warningAttrs = append(warningAttrs, Attrs(warning)...)
+	}
+
+	attrs := make([]slog.Attr, 0, 2+len(warningAttrs))
+	attrs = append(attrs, slog.Int("count", len(messages)), slog.Any("messages", messages))
+	attrs = append(attrs, warningAttrs...)
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,54 @@
+package wrap_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestWarnings(t *testing.T) {
+	var warnings wrap.Warnings
+	warnings.Add(errors.New("row 3: missing email"))
+	warnings.Add(nil)
+	warnings.Add(wrap.Error(errors.New("invalid date"), "row 7: failed to parse birthday"))
+
+	if warnings.Len() != 2 {
+		t.Errorf("unexpected number of warnings; got %d, want %d", warnings.Len(), 2)
+	}
+
+	expected := `import completed with 2 warnings
+- row 3: missing email
+- row 7: failed to parse birthday
+  - invalid date`
+
+	assertEqualStrings(t, warnings.Format("import completed with 2 warnings"), expected)
+}
+
+func TestWarningsLogValue(t *testing.T) {
+	var warnings wrap.Warnings
+	warnings.Add(&errorWithAttrs{
+		message: "missing email",
+		attrs:   []slog.Attr{slog.Int("row", 3)},
+	})
+
+	value := warnings.LogValue()
+	if value.Kind() != slog.KindGroup {
+		t.Fatalf("expected log value to be a group, got %s", value.Kind())
+	}
+
+	attrs := value.Group()
+	if len(attrs) != 3 {
+		t.Fatalf("unexpected number of attributes; got %d, want %d", len(attrs), 3)
+	}
+	if attrs[0].Key != "count" || attrs[0].Value.Int64() != 1 {
+		t.Errorf("unexpected count attribute: %v", attrs[0])
+	}
+	if attrs[1].Key != "messages" {
+		t.Errorf("unexpected messages attribute: %v", attrs[1])
+	}
+	if attrs[2].Key != "row" || attrs[2].Value.Int64() != 3 {
+		t.Errorf("unexpected warning attribute: %v", attrs[2])
+	}
+}
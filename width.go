@@ -0,0 +1,66 @@
+package wrap
+
+import (
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+var widthAware atomic.Bool
+
+// SetWidthAwareFormatting sets whether the formatter should measure message lengths in display
+// columns rather than characters, when deciding where to split long external error messages. With
+// width-aware formatting, wide characters (such as CJK characters, which take up two columns in
+// most terminals and log viewers) count as two, so that split lines have similar display widths
+// regardless of script. It is disabled by default.
+//
+// In both cases, lengths are measured in whole characters, so messages are never split in the
+// middle of a multi-byte character.
+func SetWidthAwareFormatting(enabled bool) {
+	widthAware.Store(enabled)
+}
+
+// Returns the length of the given UTF-8 message, in display columns if width-aware formatting is
+// enabled, or in characters otherwise.
+func messageWidth(message []byte) int {
+	if !widthAware.Load() {
+		return utf8.RuneCount(message)
+	}
+
+	width := 0
+	for len(message) > 0 {
+		char, size := utf8.DecodeRune(message)
+		message = message[size:]
+		if isWideRune(char) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// Returns true if the given character is displayed with double width, based on the East Asian Wide
+// and Fullwidth ranges of Unicode.
+func isWideRune(char rune) bool {
+	switch {
+	case char < 0x1100:
+		return false
+	case char <= 0x115F, // Hangul Jamo
+		char >= 0x2E80 && char <= 0x303E,   // CJK radicals, Kangxi radicals, CJK symbols
+		char >= 0x3041 && char <= 0x33FF,   // Hiragana, Katakana, CJK compatibility
+		char >= 0x3400 && char <= 0x4DBF,   // CJK unified ideographs extension A
+		char >= 0x4E00 && char <= 0x9FFF,   // CJK unified ideographs
+		char >= 0xA000 && char <= 0xA4CF,   // Yi syllables and radicals
+		char >= 0xAC00 && char <= 0xD7A3,   // Hangul syllables
+		char >= 0xF900 && char <= 0xFAFF,   // CJK compatibility ideographs
+		char >= 0xFE30 && char <= 0xFE4F,   // CJK compatibility forms
+		char >= 0xFF00 && char <= 0xFF60,   // Fullwidth forms
+		char >= 0xFFE0 && char <= 0xFFE6,   // Fullwidth signs
+		char >= 0x1F300 && char <= 0x1F64F, // Emoji
+		char >= 0x1F900 && char <= 0x1F9FF, // Supplemental symbols and pictographs
+		char >= 0x20000 && char <= 0x3FFFD: // CJK unified ideographs extensions B and beyond
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,48 @@
+package wrap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+func TestNonASCIIErrorMessage(t *testing.T) {
+	// Both messages are shorter than 64 characters (but longer than 64 bytes), so they shouldn't be
+	// split
+	err1 := errors.New("Ошибка подключения: превышено время ожидания")
+	err2 := errors.New("データベースへの接続に失敗しました: タイムアウト")
+
+	assertEqualErrorStrings(t, wrap.Error(err1, "Ошибка запроса"), `Ошибка запроса
+- Ошибка подключения: превышено время ожидания`)
+	assertEqualErrorStrings(t, wrap.Error(err2, "クエリに失敗しました"), `クエリに失敗しました
+- データベースへの接続に失敗しました: タイムアウト`)
+}
+
+func TestNonASCIIErrorMessageSplit(t *testing.T) {
+	err := errors.New(
+		strings.Repeat("ø", 40) + ": " + strings.Repeat("å", 40) + ": " + strings.Repeat("æ", 10),
+	)
+	wrapped := wrap.Error(err, "wrapped error")
+
+	expected := "wrapped error\n- " + strings.Repeat("ø", 40) +
+		"\n- " + strings.Repeat("å", 40) + ": " + strings.Repeat("æ", 10)
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
+
+func TestWidthAwareFormatting(t *testing.T) {
+	wrap.SetWidthAwareFormatting(true)
+	defer wrap.SetWidthAwareFormatting(false)
+
+	// 39 characters, but 76 display columns
+	err := errors.New("データベースへの接続に失敗しました: タイムアウトしました、再試行してください")
+	wrapped := wrap.Error(err, "クエリに失敗しました")
+
+	expected := `クエリに失敗しました
+- データベースへの接続に失敗しました
+- タイムアウトしました、再試行してください`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
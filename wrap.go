@@ -1,10 +1,25 @@
 // Package wrap provides utility functions to wrap errors with extra context in an easy-to-read
 // format.
+//
+// Errors that wrap with a message are returned as pointers, so every call creates a distinct
+// error: two separately created errors are never equal under == or [errors.Is], even if their
+// messages and wrapped errors are the same. This lets you match a specific error instance by
+// identity (e.g. for deduplication).
+//
+// Errors created by this package are immutable. Functions that add to an existing error (such as
+// [AddAttrs] and [AddStack]) return a new error that wraps it, so the new error is a new head that
+// shares the existing chain as its tail. Errors can therefore be shared between goroutines, and
+// augmented by each of them without races, since every goroutine gets its own head. To accumulate
+// additions from concurrent goroutines on a single error, use [AtomicError].
 package wrap
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+
+	"hermannm.dev/wrap/errschema"
 )
 
 // Error wraps the given error with a message for context.
@@ -31,11 +46,11 @@ import (
 // The returned error implements the Unwrap method from the standard errors package, so it works
 // with [errors.Is] and [errors.As].
 func Error(wrapped error, message string) error {
-	return wrappedError{wrapped: wrapped, message: message}
+	return runWrapHook(&wrappedError{wrapped: wrapped, message: message, caller: recordCaller()})
 }
 
 // Errorf wraps the given error with a message for context. It forwards the given message format and
-// args to [fmt.Sprintf] to construct the message.
+// args to [fmt.Errorf] to construct the message.
 //
 // Example:
 //
@@ -44,8 +59,79 @@ func Error(wrapped error, message string) error {
 //	fmt.Println(wrapped)
 //	// failed to create user with name 'hermannm'
 //	// - username already taken
+//
+// Like [fmt.Errorf], the message format may use the %w verb for error args. The errors are then
+// formatted as with %v, but are also embedded as additional causes, so that [errors.Is] and
+// [errors.As] match both the wrapped error and the embedded errors.
 func Errorf(wrapped error, messageFormat string, formatArgs ...any) error {
-	return Error(wrapped, fmt.Sprintf(messageFormat, formatArgs...))
+	// We use fmt.Errorf rather than fmt.Sprintf, since it handles %w and gives us the embedded
+	// errors through its Unwrap method (and so that go vet allows %w in calls to this function)
+	formatted := fmt.Errorf(messageFormat, formatArgs...)
+
+	err := wrappedError{
+		wrapped:       wrapped,
+		message:       formatted.Error(),
+		messageFormat: messageFormat,
+		caller:        recordCaller(),
+	}
+
+	switch formatted := formatted.(type) {
+	case interface{ Unwrap() error }:
+		return runWrapHook(&wrappedErrorWithEmbedded{
+			wrappedError: err,
+			embedded:     []error{formatted.Unwrap()},
+		})
+	case interface{ Unwrap() []error }:
+		return runWrapHook(&wrappedErrorWithEmbedded{
+			wrappedError: err,
+			embedded:     formatted.Unwrap(),
+		})
+	default:
+		return runWrapHook(&err)
+	}
+}
+
+// ErrorIf wraps the given error with a message for context, like [Error], but returns nil if the
+// given error is nil. This lets you wrap the result of a call in a single expression:
+//
+//	return wrap.ErrorIf(processOrder(order), "failed to process order")
+func ErrorIf(wrapped error, message string) error {
+	if wrapped == nil {
+		return nil
+	}
+	return Error(wrapped, message)
+}
+
+// ErrorfIf wraps the given error with a formatted message for context, like [Errorf], but returns
+// nil if the given error is nil. The message is only formatted if the error is non-nil.
+func ErrorfIf(wrapped error, messageFormat string, formatArgs ...any) error {
+	if wrapped == nil {
+		return nil
+	}
+	return Errorf(wrapped, messageFormat, formatArgs...)
+}
+
+// ErrorUnless wraps the given error with a message for context, like [Error], unless the error is
+// one of the given sentinel errors (checked with [errors.Is]), in which case it is returned as-is.
+// It returns nil if the given error is nil.
+//
+// Use this for expected errors that callers check by comparing with ==, or that would just add
+// noise if wrapped:
+//
+//	user, err := db.GetUser(ctx, userID)
+//	if err != nil {
+//		return User{}, wrap.ErrorUnless(err, "failed to fetch user", sql.ErrNoRows)
+//	}
+func ErrorUnless(wrapped error, message string, sentinels ...error) error {
+	if wrapped == nil {
+		return nil
+	}
+	for _, sentinel := range sentinels {
+		if errors.Is(wrapped, sentinel) {
+			return wrapped
+		}
+	}
+	return Error(wrapped, message)
 }
 
 // Errors wraps the given errors with a message for context.
@@ -72,21 +158,55 @@ func Errorf(wrapped error, messageFormat string, formatArgs ...any) error {
 //	//   - username too long
 //	//   - invalid email
 //
+// Nil errors are ignored, so that errors from operations that may or may not fail can be passed
+// directly.
+//
 // The returned error implements the Unwrap method from the standard errors package, so it works
 // with [errors.Is] and [errors.As].
 func Errors(message string, wrapped ...error) error {
-	return wrappedErrors{message: message, wrapped: wrapped}
+	if slices.Contains(wrapped, nil) {
+		wrapped = slices.DeleteFunc(slices.Clone(wrapped), isNil)
+	}
+	return runWrapHook(&wrappedErrors{
+		message: message,
+		wrapped: wrapped,
+		caller:  recordCaller(),
+		isIndex: newIsIndex(wrapped),
+	})
+}
+
+// ErrorsIf wraps the given errors with a message for context, like [Errors], but returns nil if
+// none of the given errors are non-nil. This lets you collect errors from a loop without checking
+// whether any of them failed:
+//
+//	errs := make([]error, len(orders))
+//	for i, order := range orders {
+//		errs[i] = processOrder(order)
+//	}
+//	return wrap.ErrorsIf("failed to process orders", errs...)
+func ErrorsIf(message string, wrapped ...error) error {
+	if !slices.ContainsFunc(wrapped, func(err error) bool { return err != nil }) {
+		return nil
+	}
+	return Errors(message, wrapped...)
 }
 
 type wrappedError struct {
 	message string
 	wrapped error
+	// The format string that the message was constructed from, if created with Errorf. Used by
+	// Fingerprint to group errors regardless of format args.
+	messageFormat string
+	// Where the error was wrapped, if enabled with SetRecordCallers.
+	caller *callSite
 }
 
 func (err wrappedError) Error() string {
 	var builder errorBuilder
 	builder.WriteString(err.message)
-	builder.writeErrorListItem(err.wrapped, 1, false)
+	if wrapped, ok := skipDuplicateMessages(err.message, err.wrapped); ok {
+		builder.writeErrorListItem(wrapped, 1, false)
+	}
 	return builder.String()
 }
 
@@ -95,14 +215,48 @@ func (err wrappedError) Unwrap() error {
 	return err.wrapped
 }
 
-// WrappingMessage implements [hermannm.dev/devlog/log.WrappedError] for log message formatting.
+// WrappingMessage implements [errschema.WrappedError] for log message formatting.
 func (err wrappedError) WrappingMessage() string {
 	return err.message
 }
 
+// A wrapped error created by Errorf with %w args. The formatter displays it like any other wrapped
+// error (the embedded errors are already part of the message), but errors.Is and errors.As also
+// check the embedded errors, through the Is and As methods.
+type wrappedErrorWithEmbedded struct {
+	wrappedError
+	embedded []error
+}
+
+// Is reports whether any of the errors embedded with %w match the target, for [errors.Is]. The
+// wrapped error is checked by errors.Is through Unwrap.
+func (err *wrappedErrorWithEmbedded) Is(target error) bool {
+	for _, embedded := range err.embedded {
+		if errors.Is(embedded, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error embedded with %w that matches the target, for [errors.As]. The wrapped
+// error is checked by errors.As through Unwrap.
+func (err *wrappedErrorWithEmbedded) As(target any) bool {
+	for _, embedded := range err.embedded {
+		if errors.As(embedded, target) {
+			return true
+		}
+	}
+	return false
+}
+
 type wrappedErrors struct {
 	message string
 	wrapped []error
+	// Where the error was wrapped, if enabled with SetRecordCallers.
+	caller *callSite
+	// Speeds up errors.Is for multi-errors with many wrapped errors (nil for smaller ones).
+	isIndex *isIndex
 }
 
 func (err wrappedErrors) Error() string {
@@ -117,44 +271,137 @@ func (err wrappedErrors) Unwrap() []error {
 	return err.wrapped
 }
 
-// WrappingMessage implements [hermannm.dev/devlog/log.WrappedError] for log message formatting.
+// WrappingMessage implements [errschema.WrappedError] for log message formatting.
 func (err wrappedErrors) WrappingMessage() string {
 	return err.message
 }
 
+// markerError is implemented by error types that attach metadata to a wrapped error without adding
+// a message of their own. The formatter skips over them, so they don't add a line to the output.
+type markerError interface {
+	error
+	unwrapMarker() error
+}
+
+// wrappingError is implemented by errors that wrap a single error with a message, such as the ones
+// returned by [Error]. The formatter displays the message as a list item, followed by the wrapped
+// error. Error types from other packages that implement the same methods are formatted the same
+// way.
+type wrappingError = errschema.WrappedError
+
+// wrappingErrors is implemented by errors that wrap multiple errors with a message, such as the
+// ones returned by [Errors]. The formatter displays the message as a list item, followed by a
+// nested list of the wrapped errors.
+type wrappingErrors = errschema.WrappedErrors
+
+// WrappingMessage returns the message of the outermost wrap layer of the given error, without the
+// errors it wraps. It returns false if the error does not wrap other errors with a message.
+//
+// This works for errors created by this package, and error types from other packages that implement
+// the same WrappingMessage method. Errors that only attach metadata (such as stack traces or codes)
+// are skipped.
+//
+// Example:
+//
+//	err := errors.New("expired token")
+//	wrapped := wrap.Error(err, "user authentication failed")
+//	message, _ := wrap.WrappingMessage(wrapped)
+//	fmt.Println(message)
+//	// user authentication failed
+func WrappingMessage(err error) (message string, ok bool) {
+	wrapping, ok := unwrapMarkers(err).(interface{ WrappingMessage() string })
+	if !ok {
+		return "", false
+	}
+	return wrapping.WrappingMessage(), true
+}
+
+// Strips any marker errors wrapping the given error, returning the first non-marker error.
+func unwrapMarkers(err error) error {
+	for {
+		marker, ok := err.(markerError)
+		if !ok {
+			return err
+		}
+		err = marker.unwrapMarker()
+	}
+}
+
 type errorBuilder struct {
 	strings.Builder
 }
 
 func (builder *errorBuilder) writeErrorListItem(wrappedErr error, indent int, partOfList bool) {
+	builder.writeCountedErrorListItem(wrappedErr, indent, partOfList, 1)
+}
+
+// Writes the given error as a list item, with the number of identical errors that it represents
+// after its message if more than 1 (see SetCollapseDuplicateErrors).
+func (builder *errorBuilder) writeCountedErrorListItem(
+	wrappedErr error,
+	indent int,
+	partOfList bool,
+	count int,
+) {
+	wrappedErr, remote := unwrapMarkersToRemote(wrappedErr)
 	builder.writeListItemPrefix(indent)
 
+	if remote {
+		builder.writeErrorMessage([]byte(remoteDivider), indent)
+		builder.writeDuplicateCount(count)
+		if partOfList {
+			indent++
+		}
+		builder.writeErrorListItem(wrappedErr, indent, false)
+		return
+	}
+
 	switch err := wrappedErr.(type) {
-	case wrappedError:
-		builder.writeErrorMessage([]byte(err.message), indent)
+	case wrappingError:
+		message := err.WrappingMessage()
+		builder.writeErrorMessage([]byte(message), indent)
+		builder.writeDuplicateCount(count)
 		if partOfList {
 			indent++
 		}
-		builder.writeErrorListItem(err.wrapped, indent, false)
-	case wrappedErrors:
-		builder.writeErrorMessage([]byte(err.message), indent)
-		if partOfList || len(err.wrapped) > 1 {
+		if wrapped, ok := skipDuplicateMessages(message, err.Unwrap()); ok {
+			builder.writeErrorListItem(wrapped, indent, false)
+		}
+	case wrappingErrors:
+		builder.writeErrorMessage([]byte(err.WrappingMessage()), indent)
+		builder.writeDuplicateCount(count)
+		wrappedErrs := err.Unwrap()
+		if partOfList || len(wrappedErrs) > 1 {
 			indent++
 		}
-		builder.writeErrorList(err.wrapped, indent)
+		builder.writeErrorList(wrappedErrs, indent)
 	default:
-		builder.writeExternalErrorMessage([]byte(err.Error()), indent, partOfList)
+		builder.writeExternalErrorMessage([]byte(leafMessage(err)), indent, partOfList)
+		builder.writeDuplicateCount(count)
 	}
 }
 
 func (builder *errorBuilder) writeErrorList(wrappedErrs []error, indent int) {
-	for _, wrappedErr := range wrappedErrs {
-		builder.writeErrorListItem(wrappedErr, indent, len(wrappedErrs) > 1)
+	var counts []int
+	if collapseDuplicateErrors.Load() {
+		wrappedErrs, counts = groupDuplicateErrors(wrappedErrs)
+	}
+
+	for i, wrappedErr := range wrappedErrs {
+		count := 1
+		if counts != nil {
+			count = counts[i]
+		}
+		// An entry for several identical errors is formatted as part of a list, even if it is the
+		// only entry, so that its wrapped errors are indented under it
+		partOfList := len(wrappedErrs) > 1 || count > 1
+		builder.writeCountedErrorListItem(wrappedErr, indent, partOfList, count)
 	}
 }
 
 // Splits error messages longer than 64 characters at ": " (typically used for error wrapping), if
-// present. Ensures that no splits are shorter than 16 characters (except the last one).
+// present. Ensures that no splits are shorter than 16 characters (except the last one). Lengths are
+// measured in characters (or display columns, see SetWidthAwareFormatting), not bytes.
 func (builder *errorBuilder) writeExternalErrorMessage(
 	message []byte,
 	indent int,
@@ -163,7 +410,7 @@ func (builder *errorBuilder) writeExternalErrorMessage(
 	const minSplitLength = 16
 	const maxSplitLength = 64
 
-	if len(message) <= maxSplitLength {
+	if messageWidth(message) <= maxSplitLength {
 		builder.writeErrorMessage(message, indent)
 		return
 	}
@@ -177,7 +424,7 @@ MessageLoop:
 			// Safe to index [i+1], since we loop until the second-to-last index
 			switch message[i+1] {
 			case ' ', '\n':
-				if i-lastWriteIndex < minSplitLength {
+				if messageWidth(message[lastWriteIndex:i]) < minSplitLength {
 					continue MessageLoop
 				}
 
@@ -193,7 +440,7 @@ MessageLoop:
 				builder.Write(message[lastWriteIndex:i])
 
 				lastWriteIndex = i + 2 // +2 for ': '
-				if len(message)-lastWriteIndex <= maxSplitLength {
+				if messageWidth(message[lastWriteIndex:]) <= maxSplitLength {
 					break MessageLoop // Remaining message is short enough, we're done
 				}
 
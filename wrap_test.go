@@ -1,9 +1,14 @@
 package wrap_test
 
 import (
+	"context"
 	"errors"
+	"io"
 	"io/fs"
+	"log/slog"
+	"net"
 	"testing"
+	"time"
 
 	"hermannm.dev/wrap"
 )
@@ -28,6 +33,90 @@ func TestErrorf(t *testing.T) {
 	assertEqualErrorStrings(t, wrapped, expected)
 }
 
+func TestErrorfWithWrappedArgs(t *testing.T) {
+	err := errors.New("connection refused")
+	embedded1 := errors.New("primary unavailable")
+	embedded2 := &fs.PathError{Op: "open", Path: "replica.conf", Err: fs.ErrNotExist}
+	wrapped := wrap.Errorf(err, "failover failed (%w, %w)", embedded1, embedded2)
+
+	expected := `failover failed (primary unavailable, open replica.conf: file does not exist)
+- connection refused`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	for _, target := range []error{err, embedded1, fs.ErrNotExist} {
+		if !errors.Is(wrapped, target) {
+			t.Errorf("expected errors.Is to match %v", target)
+		}
+	}
+
+	var pathErr *fs.PathError
+	if !errors.As(wrapped, &pathErr) || pathErr != embedded2 {
+		t.Error("expected errors.As to find embedded error")
+	}
+	if errors.Is(wrapped, io.EOF) {
+		t.Error("expected errors.Is to return false for unrelated error")
+	}
+}
+
+func TestErrorIf(t *testing.T) {
+	wrapped := wrap.ErrorIf(errors.New("error"), "wrapped error")
+
+	expected := `wrapped error
+- error`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if err := wrap.ErrorIf(nil, "wrapped error"); err != nil {
+		t.Errorf("expected ErrorIf to return nil for nil error, got %v", err)
+	}
+}
+
+func TestErrorfIf(t *testing.T) {
+	wrapped := wrap.ErrorfIf(errors.New("error"), "wrapped error %d", 1)
+
+	expected := `wrapped error 1
+- error`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if err := wrap.ErrorfIf(nil, "wrapped error %d", 1); err != nil {
+		t.Errorf("expected ErrorfIf to return nil for nil error, got %v", err)
+	}
+}
+
+func TestWrappingMessage(t *testing.T) {
+	err := errors.New("error")
+	wrapped := wrap.AddStack(wrap.Error(err, "wrapped error"))
+
+	message, ok := wrap.WrappingMessage(wrapped)
+	if !ok || message != "wrapped error" {
+		t.Errorf("unexpected wrapping message; got (%q, %t)", message, ok)
+	}
+
+	if _, ok := wrap.WrappingMessage(err); ok {
+		t.Error("expected WrappingMessage to return false for unwrapped error")
+	}
+}
+
+func TestErrorUnless(t *testing.T) {
+	errNotFound := errors.New("not found")
+
+	if err := wrap.ErrorUnless(errNotFound, "failed to fetch user", errNotFound); err != errNotFound {
+		t.Errorf("expected sentinel error to be returned as-is, got %v", err)
+	}
+
+	wrapped := wrap.ErrorUnless(errors.New("timeout"), "failed to fetch user", errNotFound)
+	expected := `failed to fetch user
+- timeout`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+
+	if err := wrap.ErrorUnless(nil, "failed to fetch user", errNotFound); err != nil {
+		t.Errorf("expected ErrorUnless to return nil for nil error, got %v", err)
+	}
+}
+
 func TestErrors(t *testing.T) {
 	err1 := errors.New("error 1")
 	err2 := errors.New("error 2")
@@ -40,6 +129,34 @@ func TestErrors(t *testing.T) {
 	assertEqualErrorStrings(t, wrapped, expected)
 }
 
+func TestErrorsSkipsNil(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	wrapped := wrap.Errors("wrapped errors", nil, err1, nil, err2)
+
+	expected := `wrapped errors
+- error 1
+- error 2`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
+
+func TestErrorsIf(t *testing.T) {
+	if err := wrap.ErrorsIf("wrapped errors", nil, nil); err != nil {
+		t.Errorf("expected nil when all errors are nil, got %v", err)
+	}
+	if err := wrap.ErrorsIf("wrapped errors"); err != nil {
+		t.Errorf("expected nil when no errors are given, got %v", err)
+	}
+
+	wrapped := wrap.ErrorsIf("wrapped errors", nil, errors.New("error 1"), nil)
+
+	expected := `wrapped errors
+- error 1`
+
+	assertEqualErrorStrings(t, wrapped, expected)
+}
+
 func TestNestedError(t *testing.T) {
 	err := errors.New("error")
 	inner := wrap.Error(err, "inner wrapped error")
@@ -237,3 +354,81 @@ want:
 `, actual, expected)
 	}
 }
+
+func TestWrappedNetError(t *testing.T) {
+	err := &net.OpError{Op: "dial", Net: "tcp", Err: context.DeadlineExceeded}
+
+	for name, wrapped := range map[string]error{
+		"Error": wrap.Error(
+			wrap.ErrorWithAttrs(err, "failed to connect", "host", "db"),
+			"query failed",
+		),
+		"Errors":        wrap.Errors("queries failed", errors.New("error"), err),
+		"Append":        wrap.Append(errors.New("error"), err),
+		"AddAttrs":      wrap.AddAttrs(err, "host", "db"),
+		"AddStack":      wrap.AddStack(err),
+		"AddErrorID":    wrap.AddErrorID(err),
+		"MarkRemote":    wrap.MarkRemote(err),
+		"ErrorWithCode": wrap.ErrorWithCode(err, "CONN", "failed to connect"),
+		"Retryable":     wrap.Retryable(err, "failed to connect"),
+		"Builder":       wrap.New(err).Code("CONN").Retryable().Err(),
+		"Map": wrap.Map(
+			wrap.Error(err, "failed to connect"),
+			func(layer wrap.Layer) wrap.Layer { return layer },
+		),
+	} {
+		netErr, ok := wrapped.(net.Error)
+		if !ok {
+			t.Errorf("%s: expected wrapped error to implement net.Error, got %T", name, wrapped)
+			continue
+		}
+		if !netErr.Timeout() {
+			t.Errorf("%s: expected Timeout to be forwarded from wrapped net.Error", name)
+		}
+		if !errors.Is(wrapped, context.DeadlineExceeded) {
+			t.Errorf("%s: expected wrapped error to match wrapped net.Error's cause", name)
+		}
+	}
+
+	for name, wrapped := range map[string]error{
+		"Error":    wrap.Error(errors.New("error"), "wrapped error"),
+		"Errors":   wrap.Errors("wrapped errors", errors.New("error 1"), errors.New("error 2")),
+		"AddAttrs": wrap.AddAttrs(errors.New("error"), "key", "value"),
+		"AddStack": wrap.AddStack(errors.New("error")),
+	} {
+		if _, ok := wrapped.(net.Error); ok {
+			t.Errorf("%s: expected error without net.Error in chain to not implement it", name)
+		}
+	}
+}
+
+func TestWrappedErrorIdentity(t *testing.T) {
+	err := errors.New("error")
+	constructors := map[string]func() error{
+		"Error":          func() error { return wrap.Error(err, "wrapped error") },
+		"Errorf":         func() error { return wrap.Errorf(err, "wrapped error %d", 1) },
+		"Errors":         func() error { return wrap.Errors("wrapped errors", err) },
+		"ErrorWithCode":  func() error { return wrap.ErrorWithCode(err, "CODE", "wrapped error") },
+		"ErrorWithAttrs": func() error { return wrap.ErrorWithAttrs(err, "wrapped error", "key", 1) },
+		"ErrorWithLevel": func() error {
+			return wrap.ErrorWithLevel(err, slog.LevelWarn, "wrapped error")
+		},
+		"Retryable":   func() error { return wrap.Retryable(err, "wrapped error") },
+		"MarkRemote":  func() error { return wrap.MarkRemote(err) },
+		"Cached":      func() error { return wrap.Cached(err, time.Time{}) },
+		"RateLimited": func() error { return wrap.RateLimited(err, 10, time.Time{}) },
+		"Builder": func() error {
+			return wrap.New(err).Code("CODE").Retryable().Context(context.Background()).Err()
+		},
+	}
+
+	for name, constructor := range constructors {
+		wrapped1, wrapped2 := constructor(), constructor()
+		if wrapped1 == wrapped2 || errors.Is(wrapped1, wrapped2) {
+			t.Errorf("%s: expected separately created errors to be distinct", name)
+		}
+		if !errors.Is(wrapped1, wrapped1) {
+			t.Errorf("%s: expected errors.Is to match the same error instance", name)
+		}
+	}
+}
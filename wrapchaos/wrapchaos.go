@@ -0,0 +1,113 @@
+// Package wrapchaos injects errors from [hermannm.dev/wrap] at instrumented call sites, for chaos
+// testing of error handling paths and logging (e.g. in a staging environment). It is disabled by
+// default, and only injects errors when enabled with [SetRate] or the WRAPCHAOS_RATE environment
+// variable.
+package wrapchaos
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"hermannm.dev/wrap"
+)
+
+// EnvRate is the environment variable read at startup to enable error injection. It should be set
+// to the probability of injecting an error at each call site, between 0 and 1 (e.g. "0.05").
+const EnvRate = "WRAPCHAOS_RATE"
+
+// SiteKey is the log attribute key for the call site name attached to injected errors.
+const SiteKey = "chaos_site"
+
+// Failure is a representative error that may be injected, with the error code used to classify it
+// (see [wrap.ErrorWithCode]).
+type Failure struct {
+	Code string
+	Err  error
+}
+
+// Stored as math.Float64bits, since there is no atomic float type.
+var rate atomic.Uint64
+
+// Used when no failures are set with SetFailures.
+var defaultFailure = Failure{Code: "CHAOS", Err: errors.New("injected failure")}
+
+var (
+	failures     = []Failure{defaultFailure}
+	failuresLock sync.RWMutex
+)
+
+func init() {
+	if envRate, err := strconv.ParseFloat(os.Getenv(EnvRate), 64); err == nil {
+		SetRate(envRate)
+	}
+}
+
+// SetRate sets the probability of injecting an error at each call to [Inject], between 0 (disabled)
+// and 1 (always inject). Values outside this range are clamped, and NaN disables injection (also
+// when set through [EnvRate]).
+func SetRate(probability float64) {
+	if math.IsNaN(probability) {
+		probability = 0
+	}
+	probability = math.Max(0, math.Min(1, probability))
+	rate.Store(math.Float64bits(probability))
+}
+
+// SetFailures sets the representative errors that [Inject] chooses from (uniformly at random).
+// Use this to inject errors matching your error taxonomy, so that injected errors exercise the same
+// handling paths as real ones. If no failures are given, a generic failure with code "CHAOS" is
+// used.
+func SetFailures(newFailures ...Failure) {
+	failuresLock.Lock()
+	defer failuresLock.Unlock()
+
+	if len(newFailures) == 0 {
+		failures = []Failure{defaultFailure}
+	} else {
+		failures = slices.Clone(newFailures)
+	}
+}
+
+// Inject instruments a call site for error injection. If the given error is non-nil, it is
+// returned as-is. Otherwise, if error injection is enabled, it returns an injected error with the
+// configured probability (see [SetRate]), and nil otherwise.
+//
+// Injected errors are chosen from the failures set with [SetFailures], wrapped with the failure's
+// code and the message "chaos: injected failure", and have the call site name attached as a log
+// attribute (with the key [SiteKey]) so they can be told apart from real errors in logs.
+//
+// Example:
+//
+//	func fetchUser(id int) (User, error) {
+//		user, err := db.GetUser(id)
+//		if err := wrapchaos.Inject("fetchUser", err); err != nil {
+//			return User{}, wrap.Error(err, "failed to fetch user")
+//		}
+//		return user, nil
+//	}
+func Inject(site string, err error) error {
+	if err != nil {
+		return err
+	}
+
+	probability := math.Float64frombits(rate.Load())
+	if probability == 0 || rand.Float64() >= probability {
+		return nil
+	}
+
+	failuresLock.RLock()
+	failure := failures[rand.Intn(len(failures))]
+	failuresLock.RUnlock()
+
+	return wrap.New(failure.Err).
+		Message("chaos: injected failure").
+		Code(failure.Code).
+		Attrs(SiteKey, site).
+		Err()
+}
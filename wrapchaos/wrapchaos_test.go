@@ -0,0 +1,69 @@
+package wrapchaos_test
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wrapchaos"
+)
+
+func TestInject(t *testing.T) {
+	wrapchaos.SetRate(1)
+	defer wrapchaos.SetRate(0)
+
+	errTimeout := errors.New("timeout")
+	wrapchaos.SetFailures(wrapchaos.Failure{Code: "TIMEOUT", Err: errTimeout})
+	defer wrapchaos.SetFailures()
+
+	err := wrapchaos.Inject("fetchUser", nil)
+	if !errors.Is(err, errTimeout) {
+		t.Fatalf("expected injected failure, got %v", err)
+	}
+
+	expected := `chaos: injected failure
+- timeout`
+	if err.Error() != expected {
+		t.Errorf("unexpected error string; got %q, want %q", err.Error(), expected)
+	}
+	if code, _ := wrap.CodeOf(err); code != "TIMEOUT" {
+		t.Errorf("unexpected code %q", code)
+	}
+
+	attrs := wrap.Attrs(err)
+	expectedAttr := slog.String(wrapchaos.SiteKey, "fetchUser")
+	if len(attrs) != 2 || !attrs[1].Equal(expectedAttr) {
+		t.Errorf("expected call site attribute, got %v", attrs)
+	}
+}
+
+func TestInjectKeepsRealErrors(t *testing.T) {
+	wrapchaos.SetRate(1)
+	defer wrapchaos.SetRate(0)
+
+	realErr := errors.New("real error")
+	if err := wrapchaos.Inject("fetchUser", realErr); err != realErr {
+		t.Errorf("expected real error to be returned as-is, got %v", err)
+	}
+}
+
+func TestInjectDisabled(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if err := wrapchaos.Inject("fetchUser", nil); err != nil {
+			t.Fatalf("expected no injected errors when disabled, got %v", err)
+		}
+	}
+}
+
+func TestSetRateNaN(t *testing.T) {
+	wrapchaos.SetRate(math.NaN())
+	defer wrapchaos.SetRate(0)
+
+	for i := 0; i < 100; i++ {
+		if err := wrapchaos.Inject("fetchUser", nil); err != nil {
+			t.Fatalf("expected NaN rate to disable injection, got %v", err)
+		}
+	}
+}
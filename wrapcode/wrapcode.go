@@ -0,0 +1,203 @@
+// Package wrapcode provides numeric error codes for [hermannm.dev/wrap], for organizations whose
+// external API contracts require numeric codes. Codes are allocated from a [Space], which divides
+// the numbers into ranges per domain (e.g. billing=1000–1999), and detects collisions when codes
+// are registered, typically in package-level variables so that collisions fail at initialization:
+//
+//	var codes = wrapcode.MustNewSpace(
+//		wrapcode.Domain{Name: "billing", First: 1000, Last: 1999},
+//		wrapcode.Domain{Name: "users", First: 2000, Last: 2999},
+//	)
+//
+//	var CodePaymentDeclined = codes.MustRegister("billing", 1001)
+//
+//	func chargeCustomer() error {
+//		// ...
+//		return CodePaymentDeclined.Wrap(err, "failed to charge customer")
+//	}
+//
+// Codes are attached to errors as strings (see [Code.String]) with [wrap.ErrorWithCode], so they
+// work with [wrap.CodeOf], logging and the other code-aware functions of the wrap package.
+package wrapcode
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"hermannm.dev/wrap"
+)
+
+// Domain is a named range of numeric codes in a [Space].
+type Domain struct {
+	// Name identifies the domain when registering codes (e.g. "billing").
+	Name string
+	// First is the lowest code in the domain.
+	First int
+	// Last is the highest code in the domain (inclusive).
+	Last int
+}
+
+// Space is a set of numeric error codes, divided into non-overlapping domains. Create it with
+// [NewSpace], and register codes with [Space.Register]. It is safe for concurrent use.
+type Space struct {
+	domains []Domain
+	// Number of digits in the highest code of the space, which all codes are padded to.
+	width int
+
+	lock  sync.Mutex
+	codes map[int]Code
+}
+
+// NewSpace creates a [Space] with the given domains. It returns an error if a domain has no name
+// or an invalid range, if two domains have the same name, or if the ranges of two domains overlap.
+func NewSpace(domains ...Domain) (*Space, error) {
+	domains = slices.Clone(domains)
+	slices.SortFunc(domains, func(domain1, domain2 Domain) int {
+		return cmp.Compare(domain1.First, domain2.First)
+	})
+
+	var errs []error
+	names := make(map[string]struct{}, len(domains))
+	for i, domain := range domains {
+		if domain.Name == "" {
+			errs = append(errs, fmt.Errorf(
+				"domain with range %s has no name",
+				domain.rangeString(),
+			))
+		}
+		if domain.First < 0 || domain.First > domain.Last {
+			errs = append(errs, fmt.Errorf(
+				"domain %q has invalid range %s",
+				domain.Name,
+				domain.rangeString(),
+			))
+		}
+		if _, duplicate := names[domain.Name]; duplicate {
+			errs = append(errs, fmt.Errorf("domain name %q is used more than once", domain.Name))
+		}
+		names[domain.Name] = struct{}{}
+
+		if i > 0 && domains[i-1].Last >= domain.First {
+			errs = append(errs, fmt.Errorf(
+				"domain %q (%s) overlaps with domain %q (%s)",
+				domain.Name,
+				domain.rangeString(),
+				domains[i-1].Name,
+				domains[i-1].rangeString(),
+			))
+		}
+	}
+	if err := wrap.ErrorsIf("invalid code space", errs...); err != nil {
+		return nil, err
+	}
+
+	var width int
+	for _, domain := range domains {
+		width = max(width, len(strconv.Itoa(domain.Last)))
+	}
+	return &Space{domains: domains, width: width, codes: make(map[int]Code)}, nil
+}
+
+// MustNewSpace creates a [Space] like [NewSpace], but panics if the domains are invalid. It is
+// meant for package-level variables, so that invalid code spaces fail at initialization.
+func MustNewSpace(domains ...Domain) *Space {
+	return wrap.Value(NewSpace(domains...)).Must("failed to create code space")
+}
+
+// Register allocates the given code in the given domain. It returns an error if the domain does
+// not exist, if the code is outside the domain's range, or if the code is already registered
+// (a collision).
+func (space *Space) Register(domain string, number int) (Code, error) {
+	index := slices.IndexFunc(space.domains, func(existing Domain) bool {
+		return existing.Name == domain
+	})
+	if index == -1 {
+		return Code{}, fmt.Errorf("unknown code domain %q", domain)
+	}
+	if found := space.domains[index]; number < found.First || number > found.Last {
+		return Code{}, fmt.Errorf(
+			"code %d is outside the range of domain %q (%s)",
+			number,
+			domain,
+			found.rangeString(),
+		)
+	}
+
+	space.lock.Lock()
+	defer space.lock.Unlock()
+
+	if existing, collision := space.codes[number]; collision {
+		return Code{}, fmt.Errorf("code %s is already registered in domain %q", existing, domain)
+	}
+
+	code := Code{domain: domain, number: number, width: space.width}
+	space.codes[number] = code
+	return code, nil
+}
+
+// MustRegister allocates the given code like [Space.Register], but panics if the code is invalid
+// or collides with an already registered code. It is meant for package-level variables, so that
+// collisions fail at initialization.
+func (space *Space) MustRegister(domain string, number int) Code {
+	return wrap.Value(space.Register(domain, number)).Must("failed to register error code")
+}
+
+// Parse returns the registered code with the given string rendering (see [Code.String]), if any.
+func (space *Space) Parse(code string) (Code, bool) {
+	number, err := strconv.Atoi(code)
+	if err != nil || len(code) != space.width {
+		return Code{}, false
+	}
+
+	space.lock.Lock()
+	defer space.lock.Unlock()
+
+	registered, ok := space.codes[number]
+	return registered, ok
+}
+
+// CodeOf returns the outermost code of the given error (see [wrap.CodeOf]), if it is a code
+// registered in the space.
+func (space *Space) CodeOf(err error) (Code, bool) {
+	code, ok := wrap.CodeOf(err)
+	if !ok {
+		return Code{}, false
+	}
+	return space.Parse(code)
+}
+
+// Code is a numeric error code registered in a [Space].
+type Code struct {
+	domain string
+	number int
+	width  int
+}
+
+// Number returns the numeric value of the code.
+func (code Code) Number() int {
+	return code.number
+}
+
+// Domain returns the name of the domain that the code belongs to.
+func (code Code) Domain() string {
+	return code.domain
+}
+
+// String returns the code as a decimal number, zero-padded to the number of digits of the highest
+// code in its space (e.g. "0042" in a space with codes up to 9999). The rendering is stable for a
+// given space, so it can be used in external API contracts.
+func (code Code) String() string {
+	return fmt.Sprintf("%0*d", code.width, code.number)
+}
+
+// Wrap wraps the given error with a message for context, and attaches the code to it (see
+// [wrap.ErrorWithCode]).
+func (code Code) Wrap(wrapped error, message string) error {
+	return wrap.ErrorWithCode(wrapped, code.String(), message)
+}
+
+func (domain Domain) rangeString() string {
+	return fmt.Sprintf("%d–%d", domain.First, domain.Last)
+}
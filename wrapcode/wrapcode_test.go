@@ -0,0 +1,83 @@
+package wrapcode_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wrapcode"
+)
+
+func TestSpace(t *testing.T) {
+	space, err := wrapcode.NewSpace(
+		wrapcode.Domain{Name: "users", First: 2000, Last: 2999},
+		wrapcode.Domain{Name: "billing", First: 10, Last: 1999},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := space.Register("billing", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.String() != "0042" || code.Number() != 42 || code.Domain() != "billing" {
+		t.Errorf("unexpected code %s (number %d, domain %s)", code, code.Number(), code.Domain())
+	}
+
+	wrapped := code.Wrap(errors.New("card declined"), "failed to charge customer")
+	if codeString, _ := wrap.CodeOf(wrapped); codeString != "0042" {
+		t.Errorf("expected code string on error, got %q", codeString)
+	}
+	if found, ok := space.CodeOf(wrap.Error(wrapped, "checkout failed")); !ok || found != code {
+		t.Errorf("expected CodeOf to find registered code, got %v", found)
+	}
+	if _, ok := space.Parse("42"); ok {
+		t.Error("expected Parse to reject code without stable padding")
+	}
+
+	for _, testCase := range []struct {
+		domain   string
+		number   int
+		expected string
+	}{
+		{"billing", 42, "already registered"},
+		{"billing", 2500, "outside the range"},
+		{"shipping", 3000, "unknown code domain"},
+	} {
+		if _, err := space.Register(testCase.domain, testCase.number); err == nil ||
+			!strings.Contains(err.Error(), testCase.expected) {
+			t.Errorf("expected error containing %q, got %v", testCase.expected, err)
+		}
+	}
+}
+
+func TestNewSpaceInvalid(t *testing.T) {
+	_, err := wrapcode.NewSpace(
+		wrapcode.Domain{Name: "billing", First: 1000, Last: 1999},
+		wrapcode.Domain{Name: "users", First: 1500, Last: 2999},
+		wrapcode.Domain{Name: "billing", First: 5000, Last: 4000},
+	)
+
+	expected := `invalid code space
+- domain "users" (1500–2999) overlaps with domain "billing" (1000–1999)
+- domain "billing" has invalid range 5000–4000
+- domain name "billing" is used more than once`
+
+	if err == nil || err.Error() != expected {
+		t.Errorf("unexpected error\nwant:\n%s\n\ngot:\n%v", expected, err)
+	}
+}
+
+func TestMustRegisterPanicsOnCollision(t *testing.T) {
+	space := wrapcode.MustNewSpace(wrapcode.Domain{Name: "billing", First: 1000, Last: 1999})
+	space.MustRegister("billing", 1001)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustRegister to panic on collision")
+		}
+	}()
+	space.MustRegister("billing", 1001)
+}
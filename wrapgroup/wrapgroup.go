@@ -0,0 +1,76 @@
+// Package wrapgroup provides helpers for running tasks in goroutine groups such as
+// golang.org/x/sync/errgroup, so that task errors are wrapped with context (using
+// [hermannm.dev/wrap/ctxwrap]) and task panics are returned as errors instead of crashing the
+// process. The helpers work with existing errgroup code without replacing the group type, so they
+// can be adopted one task at a time.
+package wrapgroup
+
+import (
+	"context"
+
+	"hermannm.dev/wrap/ctxwrap"
+	"hermannm.dev/wrap/wraprecover"
+)
+
+// Task returns a function for the Go method of an errgroup (or a similar group), which runs the
+// given function with the given context. If the function fails, its error is wrapped with the given
+// message and the context (like [ctxwrap.Error]). If the function panics, the panic is recovered
+// and returned as an error (see [wraprecover.PanicError]) with the stack trace of the panic, and
+// wrapped the same way.
+//
+// Example:
+//
+//	group, ctx := errgroup.WithContext(ctx)
+//	group.Go(wrapgroup.Task(ctx, "failed to fetch users", func(ctx context.Context) error {
+//		return fetchUsers(ctx)
+//	}))
+//	err := group.Wait()
+func Task(ctx context.Context, message string, fn func(ctx context.Context) error) func() error {
+	return func() (err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				// PanicError captures its stack trace on top of the panicking stack, so it includes
+				// the location of the panic
+				err = ctxwrap.Error(ctx, wraprecover.PanicError(value), message)
+			}
+		}()
+
+		if err := fn(ctx); err != nil {
+			return ctxwrap.Error(ctx, err, message)
+		}
+		return nil
+	}
+}
+
+// Goer is implemented by goroutine groups with a Go method taking a function that returns an error,
+// such as *errgroup.Group from golang.org/x/sync/errgroup.
+type Goer interface {
+	Go(fn func() error)
+}
+
+// Group adapts an existing goroutine group (such as an errgroup), so that every task is run with
+// [Task]. Create it with [FromErrgroup].
+type Group struct {
+	group Goer
+	ctx   context.Context
+}
+
+// FromErrgroup adapts the given group, so that tasks started through the returned [Group] are run
+// with the given context, and have their errors wrapped and panics recovered (see [Task]). Call
+// Wait on the original group to wait for the tasks.
+//
+// Example:
+//
+//	group, ctx := errgroup.WithContext(ctx)
+//	tasks := wrapgroup.FromErrgroup(ctx, group)
+//	tasks.Go("failed to fetch users", fetchUsers)
+//	tasks.Go("failed to fetch orders", fetchOrders)
+//	err := group.Wait()
+func FromErrgroup(ctx context.Context, group Goer) *Group {
+	return &Group{group: group, ctx: ctx}
+}
+
+// Go starts the given function in the underlying group, through [Task].
+func (group *Group) Go(message string, fn func(ctx context.Context) error) {
+	group.group.Go(Task(group.ctx, message, fn))
+}
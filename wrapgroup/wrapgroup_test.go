@@ -0,0 +1,108 @@
+package wrapgroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wrapgroup"
+)
+
+func TestTask(t *testing.T) {
+	err := errors.New("connection refused")
+	task := wrapgroup.Task(
+		context.Background(),
+		"failed to fetch users",
+		func(context.Context) error { return err },
+	)
+
+	wrapped := task()
+	expected := `failed to fetch users
+- connection refused`
+
+	if wrapped == nil || wrapped.Error() != expected {
+		t.Errorf("unexpected task error; got %v, want %q", wrapped, expected)
+	}
+	if !errors.Is(wrapped, err) {
+		t.Error("expected errors.Is to match task error")
+	}
+}
+
+func TestTaskRecoversPanic(t *testing.T) {
+	task := wrapgroup.Task(
+		context.Background(),
+		"failed to fetch users",
+		func(context.Context) error { panic("nil map") },
+	)
+
+	err := task()
+	expected := `failed to fetch users
+- panic
+- nil map`
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("unexpected task error; got %v, want %q", err, expected)
+	}
+
+	stack, ok := wrap.Stack(err)
+	if !ok {
+		t.Fatal("expected recovered panic to have a stack trace")
+	}
+	if !strings.Contains(stack.Render(wrap.StackFormatGo), "TestTaskRecoversPanic") {
+		t.Error("expected stack trace to include the panicking function")
+	}
+
+	var stackCount int
+	for chainErr := err; chainErr != nil; chainErr = errors.Unwrap(chainErr) {
+		if fmt.Sprintf("%T", chainErr) == "*wrap.stackError" {
+			stackCount++
+		}
+	}
+	if stackCount != 1 {
+		t.Errorf("expected recovered panic to have 1 stack trace, got %d", stackCount)
+	}
+}
+
+// Mimics errgroup.Group, without depending on golang.org/x/sync
+type group struct {
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+func (group *group) Go(fn func() error) {
+	group.wg.Add(1)
+	go func() {
+		defer group.wg.Done()
+		if err := fn(); err != nil {
+			group.errOnce.Do(func() { group.err = err })
+		}
+	}()
+}
+
+func (group *group) Wait() error {
+	group.wg.Wait()
+	return group.err
+}
+
+func TestFromErrgroup(t *testing.T) {
+	var group group
+	tasks := wrapgroup.FromErrgroup(context.Background(), &group)
+
+	tasks.Go("failed to fetch users", func(context.Context) error { return nil })
+	tasks.Go("failed to fetch orders", func(context.Context) error {
+		return errors.New("timeout")
+	})
+
+	err := group.Wait()
+	expected := `failed to fetch orders
+- timeout`
+
+	if err == nil || err.Error() != expected {
+		t.Errorf("unexpected group error; got %v, want %q", err, expected)
+	}
+}
@@ -0,0 +1,42 @@
+package wraphttp
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+// SetRateLimitHeaders sets the Retry-After, X-RateLimit-Limit and X-RateLimit-Reset headers from
+// the given error, if it or any error it wraps was marked with [wrap.RateLimited]. It returns false
+// and leaves the headers unchanged if the error is not rate limited. Retry-After is the number of
+// seconds until the limit resets (rounded up), and X-RateLimit-Reset is the reset time as a Unix
+// timestamp. Use it before writing a 429 Too Many Requests response:
+//
+//	if wraphttp.SetRateLimitHeaders(writer.Header(), err) {
+//		writer.WriteHeader(http.StatusTooManyRequests)
+//		return
+//	}
+func SetRateLimitHeaders(header http.Header, err error) (rateLimited bool) {
+	limit, resetAt, ok := wrap.RateLimit(err)
+	if !ok {
+		return false
+	}
+
+	retryAfter := math.Ceil(time.Until(resetAt).Seconds())
+	header.Set("Retry-After", strconv.Itoa(max(int(retryAfter), 0)))
+	header.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+	return true
+}
+
+// Returns the status code for a failed item or response without an explicit status: 429 Too Many
+// Requests if the error is rate limited (see wrap.RateLimited), or 500 Internal Server Error.
+func defaultErrorStatus(err error) int {
+	if _, _, rateLimited := wrap.RateLimit(err); rateLimited {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
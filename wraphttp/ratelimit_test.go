@@ -0,0 +1,53 @@
+package wraphttp_test
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wraphttp"
+)
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	resetAt := time.Now().Add(30 * time.Second)
+	err := wrap.Error(
+		wrap.RateLimited(errors.New("too many requests"), 100, resetAt),
+		"failed to fetch repositories",
+	)
+
+	header := http.Header{}
+	if !wraphttp.SetRateLimitHeaders(header, err) {
+		t.Fatal("expected SetRateLimitHeaders to return true for rate limited error")
+	}
+
+	retryAfter, parseErr := strconv.Atoi(header.Get("Retry-After"))
+	if parseErr != nil || retryAfter < 29 || retryAfter > 30 {
+		t.Errorf("unexpected Retry-After header %q", header.Get("Retry-After"))
+	}
+	if limit := header.Get("X-RateLimit-Limit"); limit != "100" {
+		t.Errorf("unexpected X-RateLimit-Limit header %q", limit)
+	}
+	if reset := header.Get("X-RateLimit-Reset"); reset != strconv.FormatInt(resetAt.Unix(), 10) {
+		t.Errorf("unexpected X-RateLimit-Reset header %q", reset)
+	}
+
+	// A reset time in the past should not give a negative Retry-After
+	header = http.Header{}
+	wraphttp.SetRateLimitHeaders(header, wrap.RateLimited(err, 100, time.Now().Add(-time.Minute)))
+	if retryAfter := header.Get("Retry-After"); retryAfter != "0" {
+		t.Errorf("expected Retry-After 0 for past reset time, got %q", retryAfter)
+	}
+}
+
+func TestSetRateLimitHeadersNotRateLimited(t *testing.T) {
+	header := http.Header{}
+	if wraphttp.SetRateLimitHeaders(header, errors.New("connection refused")) {
+		t.Error("expected SetRateLimitHeaders to return false for error without rate limit")
+	}
+	if len(header) != 0 {
+		t.Errorf("expected no headers to be set, got %v", header)
+	}
+}
@@ -0,0 +1,50 @@
+package wraphttp
+
+import (
+	"net/http"
+
+	"hermannm.dev/wrap"
+)
+
+// Transport is an [http.RoundTripper] for HTTP clients, which wraps the errors of failed requests
+// with the peer they were sent to (see [wrap.PeerError]). This makes dependency failures
+// attributable in logs without wrapping the errors of each call:
+//
+//	client := &http.Client{Transport: &wraphttp.Transport{Service: "billing"}}
+//
+// Only errors from sending requests are wrapped (e.g. connection failures), not error statuses.
+type Transport struct {
+	// Base sends the requests. If nil, [http.DefaultTransport] is used.
+	Base http.RoundTripper
+	// Service is the name of the target service, e.g. "billing". It is omitted if empty.
+	Service string
+	// Peer returns the peer to attach to the error of the given failed request. If nil, the peer is
+	// Service, the request method and URL path (e.g. "POST /invoices") and the URL host. Set it to
+	// configure which fields are logged, e.g. to leave out paths that contain IDs.
+	Peer func(request *http.Request) wrap.Peer
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (transport *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := transport.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	response, err := base.RoundTrip(request)
+	if err != nil {
+		return response, wrap.PeerError(err, transport.peer(request), "HTTP request failed")
+	}
+	return response, nil
+}
+
+func (transport *Transport) peer(request *http.Request) wrap.Peer {
+	if transport.Peer != nil {
+		return transport.Peer(request)
+	}
+	return wrap.Peer{
+		Service: transport.Service,
+		Method:  request.Method + " " + request.URL.Path,
+		Host:    request.URL.Host,
+	}
+}
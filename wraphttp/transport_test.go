@@ -0,0 +1,70 @@
+package wraphttp_test
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wraphttp"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	host := server.Listener.Addr().String()
+	server.Close()
+
+	client := &http.Client{Transport: &wraphttp.Transport{Service: "billing"}}
+	_, err := client.Post("http://"+host+"/invoices", "application/json", nil)
+	if err == nil {
+		t.Fatal("expected request to closed server to fail")
+	}
+
+	assertEqualPeerAttrs(t, err, []slog.Attr{
+		slog.String(wrap.PeerServiceKey, "billing"),
+		slog.String(wrap.PeerMethodKey, "POST /invoices"),
+		slog.String(wrap.PeerHostKey, host),
+	})
+}
+
+func TestTransportWithPeerFunc(t *testing.T) {
+	cause := errors.New("connection reset")
+	transport := &wraphttp.Transport{
+		Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, cause
+		}),
+		Service: "billing",
+		Peer: func(request *http.Request) wrap.Peer {
+			return wrap.Peer{Service: "invoices", Method: request.Method}
+		},
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://billing.internal/invoices/123", nil)
+	_, err := transport.RoundTrip(request)
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected error from base transport, got %v", err)
+	}
+
+	assertEqualPeerAttrs(t, err, []slog.Attr{
+		slog.String(wrap.PeerServiceKey, "invoices"),
+		slog.String(wrap.PeerMethodKey, "GET"),
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (roundTrip roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return roundTrip(request)
+}
+
+func assertEqualPeerAttrs(t *testing.T, err error, expected []slog.Attr) {
+	t.Helper()
+
+	attrs := wrap.Attrs(err)
+	if !slices.EqualFunc(attrs, expected, slog.Attr.Equal) {
+		t.Errorf("unexpected attributes\nwant: %v\n got: %v", expected, attrs)
+	}
+}
@@ -0,0 +1,87 @@
+// Package wraphttp provides helpers for returning errors from [hermannm.dev/wrap] in HTTP
+// responses, without leaking internal details to clients, and for attributing the errors of HTTP
+// clients to the services they call (see [Transport]).
+package wraphttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"hermannm.dev/wrap"
+)
+
+// Result is the outcome of a single item in a batch request, for [WriteMultiStatus].
+type Result struct {
+	// ID identifies the item to the client, e.g. the ID of an entity or its index in the request.
+	ID string
+	// Err is the error from processing the item, or nil if it succeeded.
+	Err error
+	// Status is the HTTP status code for the item. If 0, it defaults to 200 OK for successful
+	// items, 429 Too Many Requests for items that failed with a rate limited error (see
+	// [wrap.RateLimited]), and 500 Internal Server Error for other failed items.
+	Status int
+}
+
+// MultiStatusItem is the JSON representation of a [Result] in the body written by
+// [WriteMultiStatus].
+type MultiStatusItem struct {
+	ID     string `json:"id"`
+	Status int    `json:"status"`
+	// Code is the error code of the item (see [wrap.CodeOf]), if it failed with a code.
+	Code string `json:"code,omitempty"`
+	// Message is the public message of the item's error (see [wrap.PublicMessage]), or the text
+	// of its status code if the error has no public message. It is empty for successful items.
+	Message string `json:"message,omitempty"`
+}
+
+// MultiStatusBody is the JSON body written by [WriteMultiStatus].
+type MultiStatusBody struct {
+	Items []MultiStatusItem `json:"items"`
+}
+
+// WriteMultiStatus writes a 207 Multi-Status response with the outcome of each item of a batch
+// request that partially succeeded, on the following format:
+//
+//	{
+//		"items": [
+//			{"id": "1", "status": 200},
+//			{"id": "2", "status": 409, "code": "USER_EXISTS", "message": "User already exists"}
+//		]
+//	}
+//
+// Only the codes and public messages of errors are included, since error strings may contain
+// internal details. Log the errors separately to keep the full context.
+func WriteMultiStatus(writer http.ResponseWriter, results []Result) error {
+	body := MultiStatusBody{Items: make([]MultiStatusItem, 0, len(results))}
+	for _, result := range results {
+		body.Items = append(body.Items, newMultiStatusItem(result))
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusMultiStatus)
+	if err := json.NewEncoder(writer).Encode(body); err != nil {
+		return wrap.Error(err, "failed to encode multi-status response body")
+	}
+	return nil
+}
+
+func newMultiStatusItem(result Result) MultiStatusItem {
+	item := MultiStatusItem{ID: result.ID, Status: result.Status}
+	if result.Err == nil {
+		if item.Status == 0 {
+			item.Status = http.StatusOK
+		}
+		return item
+	}
+
+	if item.Status == 0 {
+		item.Status = defaultErrorStatus(result.Err)
+	}
+	item.Code, _ = wrap.CodeOf(result.Err)
+	if message, ok := wrap.PublicMessage(result.Err); ok {
+		item.Message = message
+	} else {
+		item.Message = http.StatusText(item.Status)
+	}
+	return item
+}
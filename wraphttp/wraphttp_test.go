@@ -0,0 +1,59 @@
+package wraphttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wraphttp"
+)
+
+func TestWriteMultiStatus(t *testing.T) {
+	conflict := wrap.ErrorWithCode(
+		wrap.ErrorWithPublicMessage(
+			errors.New(`duplicate key value violates unique constraint "users_pkey"`),
+			"failed to insert user",
+			"User already exists",
+		),
+		"USER_EXISTS",
+		"failed to create user",
+	)
+
+	recorder := httptest.NewRecorder()
+	err := wraphttp.WriteMultiStatus(recorder, []wraphttp.Result{
+		{ID: "1"},
+		{ID: "2", Err: conflict, Status: http.StatusConflict},
+		{ID: "3", Err: errors.New("connection refused")},
+		{ID: "4", Err: wrap.RateLimited(errors.New("too many requests"), 100, time.Now())},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.Code != http.StatusMultiStatus {
+		t.Errorf("expected status 207, got %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+
+	var body wraphttp.MultiStatusBody
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []wraphttp.MultiStatusItem{
+		{ID: "1", Status: 200},
+		{ID: "2", Status: 409, Code: "USER_EXISTS", Message: "User already exists"},
+		{ID: "3", Status: 500, Message: "Internal Server Error"},
+		{ID: "4", Status: 429, Message: "Too Many Requests"},
+	}
+	if !slices.Equal(body.Items, expected) {
+		t.Errorf("unexpected items\nwant: %+v\n got: %+v", expected, body.Items)
+	}
+}
@@ -0,0 +1,81 @@
+// Package wraprecover provides a last-resort crash handler, which reports panics as errors from
+// [hermannm.dev/wrap] before the process crashes, for post-mortem analysis. To recover a panic as
+// an error and keep running instead, use [wrap.Recover].
+package wraprecover
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wrapreport"
+)
+
+// FlushTimeout is the maximum time that [HandleCrash] waits for reporters to be flushed (see
+// [wrapreport.Flush]) before letting the process crash.
+const FlushTimeout = 5 * time.Second
+
+// Stored in a struct, since atomic.Pointer needs a concrete type to point to
+type crashHandler struct {
+	reporter wrapreport.Reporter
+}
+
+var handler atomic.Pointer[crashHandler]
+
+// SetCrashHandler sets the reporter that [HandleCrash] reports panics to. Pass nil to remove a
+// previously set reporter.
+func SetCrashHandler(reporter wrapreport.Reporter) {
+	if reporter == nil {
+		handler.Store(nil)
+	} else {
+		handler.Store(&crashHandler{reporter: reporter})
+	}
+}
+
+// HandleCrash recovers a panic, reports it to the reporter set with [SetCrashHandler] (as an error
+// created by [PanicError], with the stack trace of the panic), flushes all reporters (see
+// [wrapreport.Flush]), and then re-panics with the original value. This way, the panic is recorded
+// for post-mortem analysis, while the process still crashes as it would without the handler.
+//
+// It must be deferred directly at the top of main, and of every goroutine you want to cover, since
+// Go only lets a deferred function recover panics in its own goroutine:
+//
+//	func main() {
+//		wraprecover.SetCrashHandler(reporter)
+//		defer wraprecover.HandleCrash()
+//		// ...
+//	}
+func HandleCrash() {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	if crashHandler := handler.Load(); crashHandler != nil {
+		// PanicError is called from the deferred function, which runs on top of the panicking
+		// stack, so the stack trace includes the location of the panic
+		crashHandler.reporter.Report(context.Background(), PanicError(value))
+
+		ctx, cancel := context.WithTimeout(context.Background(), FlushTimeout)
+		wrapreport.Flush(ctx)
+		cancel()
+	}
+
+	panic(value)
+}
+
+// PanicError converts the given value recovered from a panic to a [wrap.PanicError], wrapped with
+// the message "panic" and with a stack trace starting at the caller (see [wrap.NewPanicError]). If
+// the value is an error, it is wrapped directly (so it still works with [errors.Is] and
+// [errors.As]). Otherwise, it is formatted with fmt.Sprint.
+//
+// Example:
+//
+//	err := wraprecover.PanicError("index out of range")
+//	fmt.Println(err)
+//	// panic
+//	// - index out of range
+func PanicError(value any) error {
+	return wrap.NewPanicError(value, "panic")
+}
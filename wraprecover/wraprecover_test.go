@@ -0,0 +1,69 @@
+package wraprecover_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wraprecover"
+	"hermannm.dev/wrap/wrapreport"
+)
+
+func TestHandleCrash(t *testing.T) {
+	var reported error
+	wraprecover.SetCrashHandler(wrapreport.ReporterFunc(func(ctx context.Context, err error) {
+		reported = err
+	}))
+	defer wraprecover.SetCrashHandler(nil)
+
+	repanicked := func() (value any) {
+		defer func() { value = recover() }()
+		panicWithHandler()
+		return nil
+	}()
+
+	if repanicked != "something went wrong" {
+		t.Errorf("expected HandleCrash to re-panic with original value, got %v", repanicked)
+	}
+
+	expected := `panic
+- something went wrong`
+	if reported == nil || reported.Error() != expected {
+		t.Fatalf("unexpected reported error; got %v, want %q", reported, expected)
+	}
+
+	var panicErr *wrap.PanicError
+	if !errors.As(reported, &panicErr) || panicErr.PanicValue() != "something went wrong" {
+		t.Error("expected reported error to be a PanicError with the panic value")
+	}
+
+	stack, ok := wrap.Stack(reported)
+	if !ok {
+		t.Fatal("expected reported error to have a stack trace")
+	}
+	rendered := stack.Render(wrap.StackFormatGo)
+	if !strings.Contains(rendered, "panicWithHandler") {
+		t.Errorf("expected stack trace to include the panicking function, got:\n%s", rendered)
+	}
+}
+
+func panicWithHandler() {
+	defer wraprecover.HandleCrash()
+	panic("something went wrong")
+}
+
+func TestPanicError(t *testing.T) {
+	err := wraprecover.PanicError(io.ErrUnexpectedEOF)
+
+	expected := `panic
+- unexpected EOF`
+	if err.Error() != expected {
+		t.Errorf("unexpected error string; got %q, want %q", err.Error(), expected)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("expected errors.Is to match panicked error")
+	}
+}
@@ -0,0 +1,142 @@
+package wrapreport
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncReporter reports errors in the background, so that reporting doesn't add latency to the
+// code paths where errors occur (such as request handlers). Errors are put on a bounded queue and
+// reported by a single worker goroutine. When the queue is full, new errors are dropped rather than
+// blocking the caller; use [AsyncReporter.Dropped] to monitor this.
+//
+// Call [AsyncReporter.Close] on shutdown to flush the queue. Async reporters are also flushed by
+// [Flush] until they are closed.
+type AsyncReporter struct {
+	reporter Reporter
+	queue    chan queuedError
+	done     chan struct{}
+	dropped  atomic.Uint64
+
+	// Guards sending on the queue against it being closed.
+	lock   sync.RWMutex
+	closed bool
+
+	// The number of errors that have been queued but not yet reported. When it drops to 0, the idle
+	// channel is closed and replaced, to wake up callers of Flush.
+	pending  atomic.Int64
+	idleLock sync.Mutex
+	idle     chan struct{}
+}
+
+type queuedError struct {
+	ctx context.Context
+	err error
+}
+
+// NewAsyncReporter creates an [AsyncReporter] that reports errors with the given reporter, queueing
+// up to queueSize errors. It starts the worker goroutine, which runs until the reporter is closed.
+func NewAsyncReporter(reporter Reporter, queueSize int) *AsyncReporter {
+	asyncReporter := &AsyncReporter{
+		reporter: reporter,
+		queue:    make(chan queuedError, queueSize),
+		done:     make(chan struct{}),
+		idle:     make(chan struct{}),
+	}
+	go asyncReporter.work()
+	RegisterFlusher(asyncReporter)
+	return asyncReporter
+}
+
+// Report queues the given error for reporting, and returns immediately. If the queue is full or the
+// reporter is closed, the error is dropped. Nil errors are ignored.
+//
+// The error is reported with the given context, but without its cancellation, since the context
+// is typically canceled (e.g. at the end of a request) before the error is reported.
+func (reporter *AsyncReporter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	reporter.lock.RLock()
+	defer reporter.lock.RUnlock()
+
+	if reporter.closed {
+		reporter.dropped.Add(1)
+		return
+	}
+
+	reporter.pending.Add(1)
+	select {
+	case reporter.queue <- queuedError{ctx: context.WithoutCancel(ctx), err: err}:
+	default:
+		reporter.dropped.Add(1)
+		reporter.donePending()
+	}
+}
+
+// Dropped returns the number of errors that have been dropped, because the queue was full or the
+// reporter was closed.
+func (reporter *AsyncReporter) Dropped() uint64 {
+	return reporter.dropped.Load()
+}
+
+// Flush waits until the queue is empty and all queued errors have been reported, while still
+// accepting new errors. If new errors keep being queued, Flush waits for those too. If the given
+// context is canceled before the queue is flushed, Flush returns the context's error.
+func (reporter *AsyncReporter) Flush(ctx context.Context) error {
+	for {
+		reporter.idleLock.Lock()
+		if reporter.pending.Load() == 0 {
+			reporter.idleLock.Unlock()
+			return nil
+		}
+		idle := reporter.idle
+		reporter.idleLock.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close stops accepting new errors, and waits for the queued errors to be reported. If the given
+// context is canceled before the queue is flushed, Close returns the context's error, and the
+// remaining errors are reported in the background. It is safe to call Close multiple times.
+func (reporter *AsyncReporter) Close(ctx context.Context) error {
+	reporter.lock.Lock()
+	if !reporter.closed {
+		reporter.closed = true
+		close(reporter.queue)
+		unregisterFlusher(reporter)
+	}
+	reporter.lock.Unlock()
+
+	select {
+	case <-reporter.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (reporter *AsyncReporter) work() {
+	defer close(reporter.done)
+
+	for queued := range reporter.queue {
+		reporter.reporter.Report(queued.ctx, queued.err)
+		reporter.donePending()
+	}
+}
+
+func (reporter *AsyncReporter) donePending() {
+	if reporter.pending.Add(-1) == 0 {
+		reporter.idleLock.Lock()
+		close(reporter.idle)
+		reporter.idle = make(chan struct{})
+		reporter.idleLock.Unlock()
+	}
+}
@@ -0,0 +1,96 @@
+package wrapreport_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap/wrapreport"
+)
+
+func TestAsyncReporter(t *testing.T) {
+	var lock sync.Mutex
+	var reported []string
+	reporter := wrapreport.NewAsyncReporter(
+		wrapreport.ReporterFunc(func(ctx context.Context, err error) {
+			lock.Lock()
+			defer lock.Unlock()
+			reported = append(reported, err.Error())
+		}),
+		10,
+	)
+
+	reporter.Report(context.Background(), errors.New("error 1"))
+	reporter.Report(context.Background(), nil)
+	reporter.Report(context.Background(), errors.New("error 2"))
+
+	if err := reporter.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error from Close: %v", err)
+	}
+
+	if len(reported) != 2 || reported[0] != "error 1" || reported[1] != "error 2" {
+		t.Errorf("unexpected reported errors: %v", reported)
+	}
+	if dropped := reporter.Dropped(); dropped != 0 {
+		t.Errorf("expected no dropped errors, got %d", dropped)
+	}
+}
+
+func TestAsyncReporterDropsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	reporter := wrapreport.NewAsyncReporter(
+		wrapreport.ReporterFunc(func(ctx context.Context, err error) {
+			started <- struct{}{}
+			<-release
+		}),
+		1,
+	)
+
+	reporter.Report(context.Background(), errors.New("error 1")) // Picked up by the worker
+	<-started
+	reporter.Report(context.Background(), errors.New("error 2")) // Queued
+	reporter.Report(context.Background(), errors.New("error 3")) // Dropped
+
+	if dropped := reporter.Dropped(); dropped != 1 {
+		t.Errorf("expected 1 dropped error, got %d", dropped)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := reporter.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected Close to time out while worker is blocked, got %v", err)
+	}
+
+	close(release)
+	<-started
+	if err := reporter.Close(context.Background()); err != nil {
+		t.Errorf("unexpected error from Close: %v", err)
+	}
+
+	reporter.Report(context.Background(), errors.New("error 4"))
+	if dropped := reporter.Dropped(); dropped != 2 {
+		t.Errorf("expected errors reported after Close to be dropped, got %d dropped", dropped)
+	}
+}
+
+func TestAsyncReporterDetachesCancellation(t *testing.T) {
+	ctxErrs := make(chan error, 1)
+	reporter := wrapreport.NewAsyncReporter(
+		wrapreport.ReporterFunc(func(ctx context.Context, err error) {
+			ctxErrs <- ctx.Err()
+		}),
+		1,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	reporter.Report(ctx, errors.New("error"))
+	reporter.Close(context.Background())
+
+	if err := <-ctxErrs; err != nil {
+		t.Errorf("expected reported context not to be canceled, got %v", err)
+	}
+}
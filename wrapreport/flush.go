@@ -0,0 +1,54 @@
+package wrapreport
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Flusher is implemented by components that buffer errors in the background (such as
+// [AsyncReporter]), and can wait for the buffered errors to be processed.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+var (
+	flushers     []Flusher
+	flushersLock sync.Mutex
+)
+
+// RegisterFlusher adds the given flusher to the ones flushed by [Flush]. Async reporters created
+// with [NewAsyncReporter] are registered automatically (and unregistered when closed).
+func RegisterFlusher(flusher Flusher) {
+	flushersLock.Lock()
+	defer flushersLock.Unlock()
+
+	flushers = append(flushers, flusher)
+}
+
+func unregisterFlusher(flusher Flusher) {
+	flushersLock.Lock()
+	defer flushersLock.Unlock()
+
+	flushers = slices.DeleteFunc(flushers, func(registered Flusher) bool {
+		return registered == flusher
+	})
+}
+
+// Flush waits for all registered flushers (see [RegisterFlusher]) to process their buffered errors,
+// in the order they were registered. Call it before the process exits (e.g. at the end of a Lambda
+// invocation or a batch job), so that errors produced during shutdown aren't lost. If the given
+// context is canceled before everything is flushed, Flush returns the context's error.
+func Flush(ctx context.Context) error {
+	flushersLock.Lock()
+	registered := slices.Clone(flushers)
+	flushersLock.Unlock()
+
+	// Flushers are called without holding the lock, so they may register other flushers
+	for _, flusher := range registered {
+		if err := flusher.Flush(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package wrapreport_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap/wrapreport"
+)
+
+func TestFlush(t *testing.T) {
+	var reported atomic.Int64
+	reporter := wrapreport.NewAsyncReporter(
+		wrapreport.ReporterFunc(func(ctx context.Context, err error) {
+			time.Sleep(time.Millisecond)
+			reported.Add(1)
+		}),
+		10,
+	)
+	defer reporter.Close(context.Background())
+
+	for i := 0; i < 5; i++ {
+		reporter.Report(context.Background(), errors.New("error"))
+	}
+
+	if err := wrapreport.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error from Flush: %v", err)
+	}
+	if count := reported.Load(); count != 5 {
+		t.Errorf("expected all 5 errors to be reported after Flush, got %d", count)
+	}
+
+	// The reporter should still accept errors after flushing
+	reporter.Report(context.Background(), errors.New("error"))
+	if err := reporter.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error from Flush: %v", err)
+	}
+	if count := reported.Load(); count != 6 {
+		t.Errorf("expected 6 errors to be reported after second Flush, got %d", count)
+	}
+}
+
+func TestFlushTimeout(t *testing.T) {
+	release := make(chan struct{})
+	reporter := wrapreport.NewAsyncReporter(
+		wrapreport.ReporterFunc(func(ctx context.Context, err error) { <-release }),
+		1,
+	)
+	defer reporter.Close(context.Background())
+	defer close(release)
+
+	reporter.Report(context.Background(), errors.New("error"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := wrapreport.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected Flush to time out while reporter is blocked, got %v", err)
+	}
+}
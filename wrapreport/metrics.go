@@ -0,0 +1,154 @@
+package wrapreport
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"sync"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+// ErrorCounter is a [Reporter] that counts reported errors by their fingerprint (see
+// [wrap.Fingerprint]), and keeps an exemplar for each count: the trace ID of the most recent error
+// with that fingerprint. This links a spike in an error metric directly to example traces.
+//
+// ErrorCounter implements [expvar.Var], so it can be published with [expvar.Publish]. Backends
+// that support exemplars (such as Prometheus) can be fed from [ErrorCounter.Counts] instead.
+//
+// Fingerprints are derived from error messages, which may contain IDs or addresses, so the number
+// of distinct fingerprints is capped (see [ErrorCounter.SetMaxFingerprints]). Errors with new
+// fingerprints beyond the cap are counted under [OtherFingerprint].
+type ErrorCounter struct {
+	traceID func(ctx context.Context) (traceID string, ok bool)
+
+	lock            sync.Mutex
+	counts          map[string]ErrorCount
+	maxFingerprints int
+}
+
+// DefaultMaxFingerprints is the default cap on the number of distinct fingerprints counted by an
+// [ErrorCounter].
+const DefaultMaxFingerprints = 1000
+
+// OtherFingerprint is the key that an [ErrorCounter] counts errors under when their fingerprint
+// is new and the cap on distinct fingerprints has been reached.
+const OtherFingerprint = "other"
+
+// ErrorCount is the number of errors with a given fingerprint reported to an [ErrorCounter].
+type ErrorCount struct {
+	// Count is the number of reported errors.
+	Count int64 `json:"count"`
+	// Exemplar is the most recently reported error with a trace ID, or nil if none of the errors
+	// had one.
+	Exemplar *Exemplar `json:"exemplar,omitempty"`
+}
+
+// Exemplar links an [ErrorCount] to an example trace.
+type Exemplar struct {
+	// TraceID is the trace ID of the error.
+	TraceID string `json:"trace_id"`
+	// Time is when the error was reported.
+	Time time.Time `json:"time"`
+}
+
+// NewErrorCounter creates an [ErrorCounter] that reads trace IDs for exemplars with the given
+// function. The trace ID is read from the contexts carried by the error (see
+// [ctxwrap.MergedContext]), and from the context given to Report if the error carries none.
+// If traceID is nil, errors are counted without exemplars.
+//
+// Example with OpenTelemetry:
+//
+//	counter := wrapreport.NewErrorCounter(func(ctx context.Context) (string, bool) {
+//		spanContext := trace.SpanContextFromContext(ctx)
+//		return spanContext.TraceID().String(), spanContext.HasTraceID()
+//	})
+//	expvar.Publish("errors", counter)
+func NewErrorCounter(traceID func(ctx context.Context) (traceID string, ok bool)) *ErrorCounter {
+	return &ErrorCounter{
+		traceID:         traceID,
+		counts:          make(map[string]ErrorCount),
+		maxFingerprints: DefaultMaxFingerprints,
+	}
+}
+
+// SetMaxFingerprints sets the cap on the number of distinct fingerprints that the counter keeps
+// (not counting [OtherFingerprint]), which is [DefaultMaxFingerprints] by default. Fingerprints
+// that are already counted keep being counted if the cap is lowered. Pass 0 (or a negative number)
+// to remove the cap.
+func (counter *ErrorCounter) SetMaxFingerprints(limit int) {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+
+	counter.maxFingerprints = limit
+}
+
+// Report counts the given error, and records its trace ID as the exemplar for its fingerprint.
+// Nil errors are ignored.
+func (counter *ErrorCounter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	fingerprint := wrap.Fingerprint(err)
+	exemplar := counter.exemplar(ctx, err)
+
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+
+	count, counted := counter.counts[fingerprint]
+	if !counted && counter.isFull() {
+		fingerprint = OtherFingerprint
+		count = counter.counts[fingerprint]
+	}
+	count.Count++
+	if exemplar != nil {
+		count.Exemplar = exemplar
+	}
+	counter.counts[fingerprint] = count
+}
+
+// Returns true if the counter has reached its cap on distinct fingerprints. Must be called with the
+// lock held.
+func (counter *ErrorCounter) isFull() bool {
+	fingerprints := len(counter.counts)
+	if _, hasOther := counter.counts[OtherFingerprint]; hasOther {
+		fingerprints--
+	}
+	return counter.maxFingerprints > 0 && fingerprints >= counter.maxFingerprints
+}
+
+func (counter *ErrorCounter) exemplar(ctx context.Context, err error) *Exemplar {
+	if counter.traceID == nil {
+		return nil
+	}
+
+	if _, hasContext := ctxwrap.ContextFrom(err); hasContext {
+		ctx = ctxwrap.MergedContext(err)
+	}
+	traceID, ok := counter.traceID(ctx)
+	if !ok {
+		return nil
+	}
+	return &Exemplar{TraceID: traceID, Time: time.Now()}
+}
+
+// Counts returns a copy of the current error counts, keyed by error fingerprint.
+func (counter *ErrorCounter) Counts() map[string]ErrorCount {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+
+	return maps.Clone(counter.counts)
+}
+
+// String returns the error counts as a JSON object keyed by error fingerprint, implementing
+// [expvar.Var].
+func (counter *ErrorCounter) String() string {
+	encoded, err := json.Marshal(counter.Counts())
+	if err != nil {
+		return "{}"
+	}
+	return string(encoded)
+}
@@ -0,0 +1,76 @@
+package wrapreport_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+	"hermannm.dev/wrap/wrapreport"
+)
+
+type traceIDKey struct{}
+
+func TestErrorCounter(t *testing.T) {
+	counter := wrapreport.NewErrorCounter(func(ctx context.Context) (string, bool) {
+		traceID, ok := ctx.Value(traceIDKey{}).(string)
+		return traceID, ok
+	})
+
+	err := errors.New("connection refused")
+	carriedCtx := context.WithValue(context.Background(), traceIDKey{}, "carried")
+	reportCtx := context.WithValue(context.Background(), traceIDKey{}, "reported")
+
+	counter.Report(reportCtx, wrap.Error(err, "failed to fetch user"))
+	counter.Report(reportCtx, ctxwrap.Error(carriedCtx, err, "failed to fetch user"))
+	counter.Report(context.Background(), wrap.Error(err, "failed to fetch user"))
+	counter.Report(context.Background(), nil)
+
+	counts := counter.Counts()
+	if len(counts) != 1 {
+		t.Fatalf("expected errors to be counted under a single fingerprint, got %v", counts)
+	}
+	count := counts[wrap.Fingerprint(wrap.Error(err, "failed to fetch user"))]
+	if count.Count != 3 {
+		t.Errorf("expected count of 3, got %d", count.Count)
+	}
+	if count.Exemplar == nil || count.Exemplar.TraceID != "carried" {
+		t.Errorf("expected exemplar with trace ID from carried context, got %+v", count.Exemplar)
+	}
+
+	var published map[string]wrapreport.ErrorCount
+	if err := json.Unmarshal([]byte(counter.String()), &published); err != nil {
+		t.Fatalf("expected expvar string to be valid JSON: %v", err)
+	}
+	if len(published) != 1 {
+		t.Errorf("unexpected published counts: %v", published)
+	}
+}
+
+func TestErrorCounterMaxFingerprints(t *testing.T) {
+	counter := wrapreport.NewErrorCounter(nil)
+	counter.SetMaxFingerprints(2)
+
+	first := errors.New("first error")
+	second := errors.New("second error")
+	counter.Report(context.Background(), first)
+	counter.Report(context.Background(), second)
+	for i := 0; i < 3; i++ {
+		counter.Report(context.Background(), fmt.Errorf("user %d not found", i))
+	}
+	counter.Report(context.Background(), first)
+
+	counts := counter.Counts()
+	if len(counts) != 3 {
+		t.Fatalf("expected 2 fingerprints and the overflow bucket, got %v", counts)
+	}
+	if count := counts[wrap.Fingerprint(first)].Count; count != 2 {
+		t.Errorf("expected counted fingerprint to keep counting past the cap, got %d", count)
+	}
+	if count := counts[wrapreport.OtherFingerprint].Count; count != 3 {
+		t.Errorf("expected overflow errors to be counted as other, got %d", count)
+	}
+}
@@ -0,0 +1,274 @@
+package wrapreport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+// RecentErrors is a [Reporter] that keeps the most recently reported errors in a fixed-size ring
+// buffer, for debug endpoints and post-mortems. It can also spill the errors to a file (see
+// [RecentErrors.SpillTo]), so that a crash-looping service still leaves structured error evidence
+// behind after it restarts.
+type RecentErrors struct {
+	lock    sync.Mutex
+	entries []RecentError
+	// Index in entries where the next error is stored, once the buffer is full.
+	next int
+	full bool
+
+	spill         *os.File
+	spillPath     string
+	spillSize     int64
+	spillMaxBytes int64
+}
+
+// RecentError is an error stored by [RecentErrors], serialized so that it can be written to disk
+// and read back after a restart.
+type RecentError struct {
+	// Time is when the error was reported.
+	Time time.Time `json:"time"`
+	// Message is the full error string.
+	Message string `json:"message"`
+	// Layers are the layers of the error (see [wrap.Flatten]).
+	Layers []RecentErrorLayer `json:"layers"`
+}
+
+// RecentErrorLayer is a layer of a [RecentError].
+type RecentErrorLayer struct {
+	// Message is the message of the layer (see [wrap.Layer]).
+	Message string `json:"message"`
+	// Depth is the number of layers above this one in the error tree.
+	Depth int `json:"depth"`
+	// Attrs are the structured log attributes of the layer, as JSON-compatible values.
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// NewRecentErrors creates a [RecentErrors] that keeps the given number of most recent errors.
+func NewRecentErrors(capacity int) *RecentErrors {
+	return &RecentErrors{entries: make([]RecentError, 0, max(capacity, 1))}
+}
+
+// Report stores the given error in the buffer, replacing the oldest stored error if the buffer is
+// full. If spilling is enabled, the error is also appended to the spill file. Nil errors are
+// ignored.
+func (recent *RecentErrors) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	entry := newRecentError(err)
+
+	recent.lock.Lock()
+	defer recent.lock.Unlock()
+
+	recent.add(entry)
+	if recent.spill != nil {
+		// Write errors are ignored, since reporting must not fail the code path reporting the
+		// error. The errors are still kept in memory.
+		_ = recent.writeSpill(entry)
+	}
+}
+
+// Errors returns the stored errors, oldest first.
+func (recent *RecentErrors) Errors() []RecentError {
+	recent.lock.Lock()
+	defer recent.lock.Unlock()
+
+	return recent.ordered()
+}
+
+// SpillTo enables persisting the buffer to the file at the given path, as JSON Lines (one
+// [RecentError] per line). Errors already in the file (e.g. from before a restart) are loaded into
+// the buffer first. Every reported error is appended to the file, and when the file would grow
+// beyond maxBytes, it is rewritten with the most recent errors in the buffer that fit in half of
+// maxBytes. This keeps the file bounded, without rewriting it on every report.
+//
+// Example:
+//
+//	recent := wrapreport.NewRecentErrors(100)
+//	if err := recent.SpillTo("/var/lib/myapp/recent-errors.jsonl", 1<<20); err != nil {
+//		slog.Warn("Failed to enable error spill file", "error", err)
+//	}
+//	defer recent.Close()
+//
+// Call [RecentErrors.Close] to close the file on shutdown.
+func (recent *RecentErrors) SpillTo(path string, maxBytes int64) error {
+	recent.lock.Lock()
+	defer recent.lock.Unlock()
+
+	if err := recent.loadSpill(path); err != nil {
+		return wrap.Errorf(err, "failed to load error spill file '%s'", path)
+	}
+
+	recent.spillPath = path
+	recent.spillMaxBytes = maxBytes
+	if err := recent.rewriteSpill(); err != nil {
+		return wrap.Errorf(err, "failed to write error spill file '%s'", path)
+	}
+	return nil
+}
+
+// Close closes the spill file, if spilling was enabled with [RecentErrors.SpillTo]. Errors reported
+// after Close are only kept in memory.
+func (recent *RecentErrors) Close() error {
+	recent.lock.Lock()
+	defer recent.lock.Unlock()
+
+	if recent.spill == nil {
+		return nil
+	}
+	err := recent.spill.Close()
+	recent.spill = nil
+	return err
+}
+
+func (recent *RecentErrors) add(entry RecentError) {
+	if !recent.full {
+		recent.entries = append(recent.entries, entry)
+		recent.full = len(recent.entries) == cap(recent.entries)
+		return
+	}
+	recent.entries[recent.next] = entry
+	recent.next = (recent.next + 1) % len(recent.entries)
+}
+
+func (recent *RecentErrors) ordered() []RecentError {
+	ordered := make([]RecentError, 0, len(recent.entries))
+	ordered = append(ordered, recent.entries[recent.next:]...)
+	return append(ordered, recent.entries[:recent.next]...)
+}
+
+func (recent *RecentErrors) loadSpill(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var entry RecentError
+		// Skips lines that fail to parse, such as a line that was cut off by a crash
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			recent.add(entry)
+		}
+	}
+	return scanner.Err()
+}
+
+func (recent *RecentErrors) writeSpill(entry RecentError) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if recent.spillSize+int64(len(line)) > recent.spillMaxBytes {
+		// The entry is already in the buffer, so rewriting the file includes it
+		return recent.rewriteSpill()
+	}
+
+	written, err := recent.spill.Write(line)
+	recent.spillSize += int64(written)
+	return err
+}
+
+// Replaces the spill file with the most recent errors in the buffer that fit in half of the size
+// limit, and opens it for appending.
+func (recent *RecentErrors) rewriteSpill() error {
+	if recent.spill != nil {
+		recent.spill.Close()
+		recent.spill = nil
+	}
+
+	var lines [][]byte
+	var size int64
+	entries := recent.ordered()
+	for i := len(entries) - 1; i >= 0; i-- {
+		line, err := json.Marshal(entries[i])
+		if err != nil {
+			continue
+		}
+		line = append(line, '\n')
+		if size+int64(len(line)) > recent.spillMaxBytes/2 {
+			break
+		}
+		lines = append(lines, line)
+		size += int64(len(line))
+	}
+
+	tempPath := recent.spillPath + ".tmp"
+	temp, err := os.Create(tempPath)
+	if err != nil {
+		return err
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if _, err := temp.Write(lines[i]); err != nil {
+			temp.Close()
+			return err
+		}
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, recent.spillPath); err != nil {
+		return err
+	}
+
+	spill, err := os.OpenFile(recent.spillPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	recent.spill = spill
+	recent.spillSize = size
+	return nil
+}
+
+func newRecentError(err error) RecentError {
+	entry := RecentError{Time: time.Now(), Message: err.Error()}
+	for _, layer := range wrap.Flatten(err) {
+		entry.Layers = append(entry.Layers, RecentErrorLayer{
+			Message: layer.Message,
+			Depth:   layer.Depth,
+			Attrs:   attrsToJSON(layer.Attrs),
+		})
+	}
+	return entry
+}
+
+func attrsToJSON(attrs []slog.Attr) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	values := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		values[attr.Key] = valueToJSON(attr.Value)
+	}
+	return values
+}
+
+func valueToJSON(value slog.Value) any {
+	value = value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		return attrsToJSON(value.Group())
+	case slog.KindAny:
+		// Arbitrary values may not be JSON-serializable, so they are stored as strings
+		return fmt.Sprint(value.Any())
+	default:
+		return value.Any()
+	}
+}
@@ -0,0 +1,105 @@
+package wrapreport_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wrapreport"
+)
+
+func TestRecentErrors(t *testing.T) {
+	recent := wrapreport.NewRecentErrors(2)
+	for i := 1; i <= 3; i++ {
+		recent.Report(context.Background(), fmt.Errorf("error %d", i))
+	}
+
+	errs := recent.Errors()
+	if len(errs) != 2 || errs[0].Message != "error 2" || errs[1].Message != "error 3" {
+		t.Errorf("expected the 2 most recent errors, oldest first, got %+v", errs)
+	}
+}
+
+func TestRecentErrorsLayers(t *testing.T) {
+	recent := wrapreport.NewRecentErrors(1)
+	err := wrap.ErrorWithAttrs(errors.New("connection refused"), "query failed", "table", "users")
+	recent.Report(context.Background(), err)
+
+	layers := recent.Errors()[0].Layers
+	if len(layers) != 2 {
+		t.Fatalf("expected 2 layers, got %+v", layers)
+	}
+	if layers[0].Message != "query failed" || layers[0].Attrs["table"] != "users" {
+		t.Errorf("unexpected outer layer %+v", layers[0])
+	}
+	if layers[1].Message != "connection refused" || layers[1].Depth != 1 {
+		t.Errorf("unexpected inner layer %+v", layers[1])
+	}
+}
+
+func TestRecentErrorsSpill(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recent-errors.jsonl")
+
+	recent := wrapreport.NewRecentErrors(10)
+	if err := recent.SpillTo(path, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	recent.Report(context.Background(), wrap.ErrorWithAttrs(errors.New("error"), "first", "id", 1))
+	recent.Report(context.Background(), errors.New("second"))
+	// Report writes to the file directly, so closing it only releases the file, like a crash would
+	if err := recent.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := wrapreport.NewRecentErrors(10)
+	if err := restarted.SpillTo(path, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+
+	errs := restarted.Errors()
+	if len(errs) != 2 || errs[0].Layers[0].Message != "first" || errs[1].Message != "second" {
+		t.Fatalf("expected errors from before restart to be loaded, got %+v", errs)
+	}
+	if id := errs[0].Layers[0].Attrs["id"]; id != float64(1) {
+		t.Errorf("expected attrs to be loaded, got %v", id)
+	}
+}
+
+func TestRecentErrorsSpillIsBounded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recent-errors.jsonl")
+	const maxBytes = 1024
+
+	recent := wrapreport.NewRecentErrors(100)
+	if err := recent.SpillTo(path, maxBytes); err != nil {
+		t.Fatal(err)
+	}
+	defer recent.Close()
+
+	for i := 1; i <= 100; i++ {
+		recent.Report(context.Background(), fmt.Errorf("error %d", i))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > maxBytes {
+		t.Errorf("expected spill file to be at most %d bytes, got %d", maxBytes, info.Size())
+	}
+
+	restarted := wrapreport.NewRecentErrors(100)
+	if err := restarted.SpillTo(path, maxBytes); err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+
+	errs := restarted.Errors()
+	if len(errs) == 0 || errs[len(errs)-1].Message != "error 100" {
+		t.Errorf("expected spill file to keep the most recent errors, got %+v", errs)
+	}
+}
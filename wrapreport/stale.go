@@ -0,0 +1,37 @@
+package wrapreport
+
+import (
+	"context"
+	"time"
+
+	"hermannm.dev/wrap"
+)
+
+// WarnStale returns a [Reporter] that reports errors with the given reporter, and first calls warn
+// with every error that is older than maxAge (see [wrap.Age]). Old errors being reported often
+// point to bugs where stale errors are returned from caches or retried state machines. Errors are
+// only checked if creation times are recorded (see [wrap.SetRecordCreationTimes]) or the errors
+// are marked with [wrap.Cached].
+//
+// Example:
+//
+//	wrap.SetRecordCreationTimes(true)
+//	reporter = wrapreport.WarnStale(
+//		reporter,
+//		time.Minute,
+//		func(ctx context.Context, err error, age time.Duration) {
+//			slog.WarnContext(ctx, "Reported error is stale", "age", age, "error", err)
+//		},
+//	)
+func WarnStale(
+	reporter Reporter,
+	maxAge time.Duration,
+	warn func(ctx context.Context, err error, age time.Duration),
+) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error) {
+		if age, ok := wrap.Age(err); ok && age > maxAge {
+			warn(ctx, err, age)
+		}
+		reporter.Report(ctx, err)
+	})
+}
@@ -0,0 +1,37 @@
+package wrapreport_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wrapreport"
+)
+
+func TestWarnStale(t *testing.T) {
+	var reported []error
+	var warned []error
+	reporter := wrapreport.WarnStale(
+		wrapreport.ReporterFunc(func(ctx context.Context, err error) {
+			reported = append(reported, err)
+		}),
+		time.Minute,
+		func(ctx context.Context, err error, age time.Duration) {
+			warned = append(warned, err)
+		},
+	)
+
+	fresh := wrap.Cached(errors.New("fresh error"), time.Now())
+	stale := wrap.Cached(errors.New("stale error"), time.Now().Add(-time.Hour))
+	reporter.Report(context.Background(), fresh)
+	reporter.Report(context.Background(), stale)
+
+	if len(reported) != 2 {
+		t.Errorf("expected all errors to be reported, got %v", reported)
+	}
+	if len(warned) != 1 || warned[0] != stale {
+		t.Errorf("expected warning for stale error only, got %v", warned)
+	}
+}
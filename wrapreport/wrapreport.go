@@ -0,0 +1,22 @@
+// Package wrapreport provides helpers for reporting errors from [hermannm.dev/wrap] to error
+// trackers (such as Sentry), without slowing down the code paths where the errors occur.
+package wrapreport
+
+import (
+	"context"
+)
+
+// Reporter reports errors to an error tracker. Implementations typically convert the error to the
+// tracker's event format (using e.g. [hermannm.dev/wrap.Attrs] and
+// [hermannm.dev/wrap/ctxwrap.ExtractAttrs] for structured data) and send it.
+type Reporter interface {
+	Report(ctx context.Context, err error)
+}
+
+// ReporterFunc is a function that implements [Reporter].
+type ReporterFunc func(ctx context.Context, err error)
+
+// Report calls the function with the given context and error.
+func (report ReporterFunc) Report(ctx context.Context, err error) {
+	report(ctx, err)
+}
@@ -0,0 +1,158 @@
+package wrapslog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// HandlerOptions configures the handler returned by [NewHandler].
+type HandlerOptions struct {
+	// Keys limits the expansion of errors to attributes with these keys (at any nesting level).
+	// Expanding an error collects the attributes of its whole chain, so use this to control the
+	// cost in hot paths. If empty, errors are expanded under all keys.
+	Keys []string
+}
+
+// NewHandler returns a [slog.Handler] middleware that expands errors in log attributes into
+// structured form, and passes the records on to the given handler. Errors are detected in the
+// values of all attributes, including attributes nested in groups and attributes added with
+// [slog.Logger.With], not only under a conventional "error" key.
+//
+// Each error attribute is replaced by a group with the same key, containing the error message
+// (and its leaf types, if enabled with [SetIncludeLeafTypes]), the log attributes attached to the
+// error, and the log attributes extracted from its context (see [AddError]). Like in AddError,
+// debug attributes (see [wrap.DebugAttr]) are included if the logger level set with
+// [SetLoggerLevel] is DEBUG or lower. This is checked for each record, also for errors added with
+// [slog.Logger.With], so that changing the level at runtime applies to all errors.
+//
+// Example:
+//
+//	logger := slog.New(wrapslog.NewHandler(slog.NewJSONHandler(os.Stdout, nil), nil))
+//	logger.Error("request failed", "cause", err)
+//	// {"time":"...","level":"ERROR","msg":"request failed","cause":{"message":"...","user_id":1}}
+//
+// If options is nil, the default options are used.
+func NewHandler(next slog.Handler, options *HandlerOptions) slog.Handler {
+	handler := &errorHandler{next: next}
+	if options != nil {
+		handler.keys = slices.Clone(options.Keys)
+	}
+	return handler
+}
+
+type errorHandler struct {
+	next slog.Handler
+	// The next handler with errors in the attributes from WithAttrs expanded with debug attributes
+	// included, so that the level can be checked for each record in Handle. It is nil if there are
+	// no such errors, in which case next is used for all records.
+	nextWithDebug slog.Handler
+	keys          []string
+}
+
+func (handler *errorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.next.Enabled(ctx, level)
+}
+
+func (handler *errorHandler) Handle(ctx context.Context, record slog.Record) error {
+	includeDebug := debugEnabled()
+
+	expanded := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(handler.expandErrors(attr, includeDebug))
+		return true
+	})
+	return handler.nextHandler(includeDebug).Handle(ctx, expanded)
+}
+
+// Returns the next handler to pass records to, with the attributes from WithAttrs expanded with or
+// without debug attributes.
+func (handler *errorHandler) nextHandler(includeDebug bool) slog.Handler {
+	if includeDebug && handler.nextWithDebug != nil {
+		return handler.nextWithDebug
+	}
+	return handler.next
+}
+
+func (handler *errorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	withAttrs := &errorHandler{
+		next: handler.next.WithAttrs(handler.expandAllErrors(attrs, false)),
+		keys: handler.keys,
+	}
+	// Errors are expanded both with and without debug attributes, since the level may change
+	// between now and when records are handled
+	if handler.nextWithDebug != nil || handler.containsErrors(attrs) {
+		withAttrs.nextWithDebug = handler.nextHandler(true).WithAttrs(
+			handler.expandAllErrors(attrs, true),
+		)
+	}
+	return withAttrs
+}
+
+func (handler *errorHandler) WithGroup(name string) slog.Handler {
+	withGroup := &errorHandler{next: handler.next.WithGroup(name), keys: handler.keys}
+	if handler.nextWithDebug != nil {
+		withGroup.nextWithDebug = handler.nextWithDebug.WithGroup(name)
+	}
+	return withGroup
+}
+
+func (handler *errorHandler) expandAllErrors(attrs []slog.Attr, includeDebug bool) []slog.Attr {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		expanded[i] = handler.expandErrors(attr, includeDebug)
+	}
+	return expanded
+}
+
+// Replaces the given attribute with a group describing the error if its value is an error (and
+// its key is allowed), or expands errors in the attributes of the group if it is a group.
+func (handler *errorHandler) expandErrors(attr slog.Attr, includeDebug bool) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindAny:
+		err, ok := handler.expandableError(attr)
+		if !ok {
+			return attr
+		}
+
+		groupAttrs := errorDescriptionAttrs(err)
+		for _, errAttr := range structuredAttrs(err, includeDebug) {
+			groupAttrs = append(groupAttrs, errAttr)
+		}
+		return slog.Group(attr.Key, groupAttrs...)
+	case slog.KindGroup:
+		expanded := handler.expandAllErrors(attr.Value.Group(), includeDebug)
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(expanded...)}
+	default:
+		return attr
+	}
+}
+
+// Returns true if any of the given attributes (or attributes nested in groups) is an error that is
+// expanded by expandErrors.
+func (handler *errorHandler) containsErrors(attrs []slog.Attr) bool {
+	for _, attr := range attrs {
+		switch attr.Value.Kind() {
+		case slog.KindAny:
+			if _, ok := handler.expandableError(attr); ok {
+				return true
+			}
+		case slog.KindGroup:
+			if handler.containsErrors(attr.Value.Group()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the error in the value of the given attribute, if it is a non-nil error and the key of
+// the attribute is allowed.
+func (handler *errorHandler) expandableError(attr slog.Attr) (err error, ok bool) {
+	err, isError := attr.Value.Any().(error)
+	return err, isError && err != nil && handler.shouldExpand(attr.Key)
+}
+
+func (handler *errorHandler) shouldExpand(key string) bool {
+	return len(handler.keys) == 0 || slices.Contains(handler.keys, key)
+}
@@ -0,0 +1,118 @@
+package wrapslog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/wrapslog"
+)
+
+func TestHandler(t *testing.T) {
+	err := wrap.ErrorWithAttrs(errors.New("connection refused"), "query failed", "table", "users")
+
+	output := logWithHandler(t, nil, func(logger *slog.Logger) {
+		logger.With("startup_err", err).Error(
+			"request failed",
+			"cause", err,
+			slog.Group("details", "nested", err, "count", 2),
+		)
+	})
+
+	expectedGroup := map[string]any{"message": err.Error(), "table": "users"}
+	assertJSONField(t, output, expectedGroup, "startup_err")
+	assertJSONField(t, output, expectedGroup, "cause")
+	assertJSONField(t, output, expectedGroup, "details", "nested")
+	assertJSONField(t, output, 2.0, "details", "count")
+}
+
+func TestHandlerWithKeys(t *testing.T) {
+	err := wrap.ErrorWithAttrs(errors.New("connection refused"), "query failed", "table", "users")
+
+	output := logWithHandler(
+		t,
+		&wrapslog.HandlerOptions{Keys: []string{"cause"}},
+		func(logger *slog.Logger) {
+			logger.Error("request failed", "cause", err, "other", err)
+		},
+	)
+
+	assertJSONField(t, output, map[string]any{"message": err.Error(), "table": "users"}, "cause")
+	assertJSONField(t, output, err.Error(), "other")
+}
+
+func TestHandlerWithDebugAttrs(t *testing.T) {
+	err := wrap.ErrorWithAttrs(errors.New("error"), "wrapped error", wrap.DebugAttr("key", "value"))
+
+	var level slog.LevelVar
+	level.Set(slog.LevelInfo)
+	wrapslog.SetLoggerLevel(&level)
+	defer wrapslog.SetLoggerLevel(nil)
+
+	var output bytes.Buffer
+	handler := slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: &level})
+	logger := slog.New(wrapslog.NewHandler(handler, nil)).With("startup_err", err)
+
+	for _, debug := range []bool{false, true, false} {
+		if debug {
+			level.Set(slog.LevelDebug)
+		} else {
+			level.Set(slog.LevelInfo)
+		}
+
+		output.Reset()
+		logger.WithGroup("request").Error("request failed", "cause", err)
+
+		var fields map[string]any
+		if err := json.Unmarshal(output.Bytes(), &fields); err != nil {
+			t.Fatalf("failed to decode log output %q: %v", output.String(), err)
+		}
+
+		expectedGroup := map[string]any{"message": err.Error()}
+		if debug {
+			expectedGroup["key"] = "value"
+		}
+		assertJSONField(t, fields, expectedGroup, "startup_err")
+		assertJSONField(t, fields, expectedGroup, "request", "cause")
+	}
+}
+
+func logWithHandler(
+	t *testing.T,
+	options *wrapslog.HandlerOptions,
+	log func(logger *slog.Logger),
+) map[string]any {
+	t.Helper()
+
+	var output bytes.Buffer
+	log(slog.New(wrapslog.NewHandler(slog.NewJSONHandler(&output, nil), options)))
+
+	var fields map[string]any
+	if err := json.Unmarshal(output.Bytes(), &fields); err != nil {
+		t.Fatalf("failed to decode log output %q: %v", output.String(), err)
+	}
+	return fields
+}
+
+func assertJSONField(t *testing.T, fields map[string]any, expected any, path ...string) {
+	t.Helper()
+
+	var value any = fields
+	for _, key := range path {
+		group, ok := value.(map[string]any)
+		if !ok {
+			t.Errorf("expected group at %v, got %v", path, value)
+			return
+		}
+		value = group[key]
+	}
+
+	expectedJSON, _ := json.Marshal(expected)
+	actualJSON, _ := json.Marshal(value)
+	if !bytes.Equal(expectedJSON, actualJSON) {
+		t.Errorf("unexpected value at %v\nwant: %s\n got: %s", path, expectedJSON, actualJSON)
+	}
+}
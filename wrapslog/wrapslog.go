@@ -0,0 +1,92 @@
+// Package wrapslog provides helpers for logging errors from [hermannm.dev/wrap] with [log/slog],
+// including the structured log attributes and contexts attached to the errors.
+package wrapslog
+
+import (
+	"log/slog"
+	"slices"
+	"sync/atomic"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+)
+
+// ErrorKey is the key used for the error group added by [AddError].
+const ErrorKey = "error"
+
+// AddError adds the given error to the log record, for use in custom [slog.Handler]s or when
+// bridging slog records to other logging systems. It adds:
+//   - An "error" group with the error message (and the types of its leaf errors, if enabled with
+//     [SetIncludeLeafTypes])
+//   - The log attributes attached to the error and the errors it wraps (see [wrap.Attrs]), with
+//     debug attributes (see [wrap.DebugAttr]) only if the logger level set with [SetLoggerLevel]
+//     is DEBUG or lower
+//   - The log attributes extracted from the context attached to the error (see
+//     [ctxwrap.ExtractAttrs]), if the error was created with a context
+//
+// If the error is nil, the record is left unchanged.
+func AddError(record *slog.Record, err error) {
+	if err == nil {
+		return
+	}
+
+	record.AddAttrs(slog.Group(ErrorKey, errorDescriptionAttrs(err)...))
+	record.AddAttrs(structuredAttrs(err, debugEnabled())...)
+}
+
+// Returns the attributes that describe the given error itself: its message, and the types of its
+// leaf errors if enabled with SetIncludeLeafTypes.
+func errorDescriptionAttrs(err error) []any {
+	attrs := []any{slog.String("message", err.Error())}
+	if includeLeafTypes.Load() {
+		attrs = append(attrs, slog.Any("leaf_types", wrap.LeafTypes(err)))
+	}
+	return attrs
+}
+
+// Returns the log attributes attached to the given error, with debug attributes only if
+// includeDebug is true, followed by the attributes extracted from its context.
+func structuredAttrs(err error, includeDebug bool) []slog.Attr {
+	attrs := wrap.FilterDebugAttrs(wrap.Attrs(err), includeDebug)
+	return append(slices.Clip(attrs), ctxwrap.ExtractAttrs(err)...)
+}
+
+var includeLeafTypes atomic.Bool
+
+// SetIncludeLeafTypes sets whether [AddError] should include the Go type names of the leaf errors
+// in the error chain (see [wrap.LeafTypes]) as a "leaf_types" field in the error group. Knowing the
+// concrete type of the root cause (such as *net.OpError) is often a useful triage signal. It is
+// disabled by default.
+func SetIncludeLeafTypes(include bool) {
+	includeLeafTypes.Store(include)
+}
+
+// Stored in a struct, since atomic.Pointer needs a concrete type to point to
+type loggerLevel struct {
+	leveler slog.Leveler
+}
+
+var currentLoggerLevel atomic.Pointer[loggerLevel]
+
+// SetLoggerLevel sets the level of the logger that [AddError] and the handler from [NewHandler] add
+// errors for, which decides whether debug attributes (see [wrap.DebugAttr]) are included. Pass the
+// same [slog.Leveler] as the one given to your handler (such as a [slog.LevelVar]), so that turning
+// on debug logging at runtime also turns on debug attributes:
+//
+//	var level slog.LevelVar
+//	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &level})
+//	wrapslog.SetLoggerLevel(&level)
+//
+// Pass nil to remove a previously set level. Without a level, debug attributes are left out.
+func SetLoggerLevel(level slog.Leveler) {
+	if level == nil {
+		currentLoggerLevel.Store(nil)
+	} else {
+		currentLoggerLevel.Store(&loggerLevel{leveler: level})
+	}
+}
+
+func debugEnabled() bool {
+	level := currentLoggerLevel.Load()
+	return level != nil && level.leveler.Level() <= slog.LevelDebug
+}
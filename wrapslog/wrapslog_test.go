@@ -0,0 +1,130 @@
+package wrapslog_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+	"hermannm.dev/wrap/wrapslog"
+)
+
+func TestAddError(t *testing.T) {
+	outerCtx := ctxwrap.WithAttrs(context.Background(), "user_id", 123)
+	innerCtx := ctxwrap.WithAttrs(outerCtx, "request_id", "abc")
+
+	err := errors.New("connection refused")
+	inner := ctxwrap.ErrorWithAttrs(innerCtx, err, "database query failed", "table", "users")
+	outer := ctxwrap.Error(outerCtx, inner, "failed to fetch user")
+
+	record := slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+	wrapslog.AddError(&record, outer)
+
+	expected := []slog.Attr{
+		slog.Group("error", slog.String("message", outer.Error())),
+		slog.String("table", "users"),
+		slog.Int("user_id", 123),
+		slog.String("request_id", "abc"),
+	}
+
+	assertRecordAttrs(t, record, expected)
+}
+
+func TestAddErrorWithoutContext(t *testing.T) {
+	err := wrap.Error(errors.New("error"), "wrapped error")
+
+	record := slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+	wrapslog.AddError(&record, err)
+
+	assertRecordAttrs(
+		t,
+		record,
+		[]slog.Attr{slog.Group("error", slog.String("message", err.Error()))},
+	)
+}
+
+func TestAddErrorWithLeafTypes(t *testing.T) {
+	wrapslog.SetIncludeLeafTypes(true)
+	defer wrapslog.SetIncludeLeafTypes(false)
+
+	err := wrap.Error(errors.New("error"), "wrapped error")
+
+	record := slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+	wrapslog.AddError(&record, err)
+
+	var leafTypes any
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key != "error" {
+			return true
+		}
+		for _, errorAttr := range attr.Value.Group() {
+			if errorAttr.Key == "leaf_types" {
+				leafTypes = errorAttr.Value.Any()
+			}
+		}
+		return true
+	})
+
+	expected := []string{"*errors.errorString"}
+	if types, ok := leafTypes.([]string); !ok || !slices.Equal(types, expected) {
+		t.Errorf("unexpected leaf types in error group; got %v", leafTypes)
+	}
+}
+
+func TestAddNilError(t *testing.T) {
+	record := slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+	wrapslog.AddError(&record, nil)
+
+	assertRecordAttrs(t, record, nil)
+}
+
+func assertRecordAttrs(t *testing.T, record slog.Record, expected []slog.Attr) {
+	t.Helper()
+
+	var actual []slog.Attr
+	record.Attrs(func(attr slog.Attr) bool {
+		actual = append(actual, attr)
+		return true
+	})
+
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected record attributes; got %v, want %v", actual, expected)
+	}
+	for i := range actual {
+		if !actual[i].Equal(expected[i]) {
+			t.Errorf("unexpected attribute at index %d; got %v, want %v", i, actual[i], expected[i])
+		}
+	}
+}
+
+func TestAddErrorWithDebugAttrs(t *testing.T) {
+	err := wrap.ErrorWithAttrs(errors.New("error"), "wrapped error", wrap.DebugAttr("key", "value"))
+
+	record := slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+	wrapslog.AddError(&record, err)
+	assertRecordAttrs(
+		t,
+		record,
+		[]slog.Attr{slog.Group("error", slog.String("message", err.Error()))},
+	)
+
+	var level slog.LevelVar
+	level.Set(slog.LevelDebug)
+	wrapslog.SetLoggerLevel(&level)
+	defer wrapslog.SetLoggerLevel(nil)
+
+	record = slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+	wrapslog.AddError(&record, err)
+	assertRecordAttrs(
+		t,
+		record,
+		[]slog.Attr{
+			slog.Group("error", slog.String("message", err.Error())),
+			slog.String("key", "value"),
+		},
+	)
+}
@@ -0,0 +1,256 @@
+// Package wraptest provides a conformance suite for code that formats, serializes or rewraps errors
+// from [hermannm.dev/wrap], such as custom log formatters, logging library integrations and error
+// reporters. Run the suite from your own tests, so that upgrades of this module that would break
+// your code are caught:
+//
+//	func TestErrorFormatter(t *testing.T) {
+//		wraptest.TestFormatter(t, myapp.FormatError)
+//	}
+package wraptest
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"hermannm.dev/wrap"
+)
+
+// Case is an error used by the conformance suite, along with the properties that code handling the
+// error is required to preserve.
+type Case struct {
+	// Name identifies the case in subtest names.
+	Name string
+	// Err is the error to test with.
+	Err error
+	// Messages are the messages of the layers of the error, outermost first. Formatted output must
+	// contain them in this order.
+	Messages []string
+	// Attrs are the structured log attributes attached to the error (as returned by
+	// [wrap.Attrs]).
+	Attrs []slog.Attr
+	// Targets are errors wrapped by the error, which [errors.Is] must match.
+	Targets []error
+}
+
+// Cases returns the errors used by the conformance suite. It covers single-error and multi-error
+// chains, attributes, codes, sentinels, stack traces and errors embedded with %w in [wrap.Errorf].
+// New cases may be added in later versions of this module, as new features are added. The errors
+// are created anew on every call.
+func Cases() []Case {
+	var cases []Case
+
+	root := errors.New("connection refused")
+	cases = append(cases, Case{
+		Name:     "chain",
+		Err:      wrap.Error(wrap.Error(root, "database query failed"), "failed to fetch user"),
+		Messages: []string{"failed to fetch user", "database query failed", "connection refused"},
+		Targets:  []error{root},
+	})
+
+	root = errors.New("username already taken")
+	cases = append(cases, Case{
+		Name: "attrs",
+		Err: wrap.ErrorWithCode(
+			wrap.ErrorWithAttrs(
+				wrap.AddAttrs(root, "username", "hermannm"),
+				"insert failed",
+				"table",
+				"users",
+			),
+			"USER_CONFLICT",
+			"failed to create user",
+		),
+		Messages: []string{"failed to create user", "insert failed", "username already taken"},
+		Attrs: []slog.Attr{
+			slog.String(wrap.CodeKey, "USER_CONFLICT"),
+			slog.String("table", "users"),
+			slog.String("username", "hermannm"),
+		},
+		Targets: []error{root},
+	})
+
+	first, second := errors.New("invalid email"), errors.New("invalid phone number")
+	cases = append(cases, Case{
+		Name: "multi-error",
+		Err: wrap.Error(
+			wrap.Errors(
+				"validation failed",
+				first,
+				wrap.Error(second, "failed to parse phone number"),
+			),
+			"failed to register user",
+		),
+		Messages: []string{
+			"failed to register user",
+			"validation failed",
+			"invalid email",
+			"failed to parse phone number",
+			"invalid phone number",
+		},
+		Targets: []error{first, second},
+	})
+
+	sentinel := wrap.NewSentinel(
+		"quota exceeded",
+		wrap.WithCode("QUOTA"),
+		wrap.WithAttrs("domain", "billing"),
+	)
+	cases = append(cases, Case{
+		Name:     "sentinel",
+		Err:      wrap.AddStack(wrap.Error(sentinel, "failed to charge customer")),
+		Messages: []string{"failed to charge customer", "quota exceeded"},
+		Attrs:    []slog.Attr{slog.String(wrap.CodeKey, "QUOTA"), slog.String("domain", "billing")},
+		Targets:  []error{sentinel},
+	})
+
+	root, embedded := errors.New("timeout"), errors.New("primary unavailable")
+	cases = append(cases, Case{
+		Name:     "embedded",
+		Err:      wrap.Errorf(root, "failover failed: %w", embedded),
+		Messages: []string{"failover failed: primary unavailable", "timeout"},
+		Targets:  []error{root, embedded},
+	})
+
+	return cases
+}
+
+// TestFormatter checks that the given function, which formats an error to a string (such as a log
+// line or a serialized error), preserves the messages of the error's layers in order, and includes
+// the keys and values of the error's attributes.
+func TestFormatter(t *testing.T, format func(err error) string) {
+	t.Helper()
+
+	for _, testCase := range Cases() {
+		t.Run(testCase.Name, func(t *testing.T) {
+			output := format(testCase.Err)
+
+			checkMessageOrder(t, output, testCase.Messages)
+
+			for _, attr := range testCase.Attrs {
+				value := attr.Value.Resolve().String()
+				if !strings.Contains(output, attr.Key) || !strings.Contains(output, value) {
+					t.Errorf("output is missing attribute %s=%s:\n%s", attr.Key, value, output)
+				}
+			}
+		})
+	}
+}
+
+// TestWrapper checks that the given function, which returns an error derived from the given error
+// (such as a rehydrated error or an error with added context), keeps [errors.Is] working for the
+// wrapped errors, keeps the attributes of the error (as returned by [wrap.Attrs]), and keeps the
+// messages of the error's layers in order in the error string.
+func TestWrapper(t *testing.T, rewrap func(err error) error) {
+	t.Helper()
+
+	for _, testCase := range Cases() {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := rewrap(testCase.Err)
+			if err == nil {
+				t.Fatal("wrapper returned nil error")
+			}
+
+			for _, target := range testCase.Targets {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is does not match wrapped error %q", target)
+				}
+			}
+
+			attrs := wrap.Attrs(err)
+			for _, expected := range testCase.Attrs {
+				if !containsAttr(attrs, expected) {
+					t.Errorf("wrap.Attrs is missing attribute %v (got %v)", expected, attrs)
+				}
+			}
+
+			checkMessageOrder(t, err.Error(), testCase.Messages)
+		})
+	}
+}
+
+// TestLogger checks that a logging library integration (such as a [slog.Handler], or an adapter for
+// zap, zerolog or logrus) logs errors with their structured data. The given function should log the
+// error with the integration under test, and return the structured output of the log entry as
+// JSON-compatible values (typically by decoding a JSON log line into a map).
+//
+// The output must contain a string field (at any nesting level) with the messages of the error's
+// layers in order, and a field for each of the error's attributes, with the attribute key as the
+// field name and the attribute value as the field value (compared as strings, after JSON decoding).
+// Attributes may be nested in groups, but must be separate fields: attributes that are only
+// included in a formatted string do not pass.
+func TestLogger(t *testing.T, logError func(err error) map[string]any) {
+	t.Helper()
+
+	for _, testCase := range Cases() {
+		t.Run(testCase.Name, func(t *testing.T) {
+			output := logError(testCase.Err)
+
+			if !anyField(output, func(key string, value any) bool {
+				message, isString := value.(string)
+				return isString && containsMessagesInOrder(message, testCase.Messages)
+			}) {
+				t.Errorf(
+					"output has no field with messages %q in order: %v",
+					testCase.Messages,
+					output,
+				)
+			}
+
+			for _, attr := range testCase.Attrs {
+				expected := attr.Value.Resolve().String()
+				if !anyField(output, func(key string, value any) bool {
+					return key == attr.Key && fmt.Sprint(value) == expected
+				}) {
+					t.Errorf("output is missing field %s=%s: %v", attr.Key, expected, output)
+				}
+			}
+		})
+	}
+}
+
+// Returns true if the given predicate matches any field in the given structured output, including
+// fields in nested groups.
+func anyField(fields map[string]any, predicate func(key string, value any) bool) bool {
+	for key, value := range fields {
+		if predicate(key, value) {
+			return true
+		}
+		if group, isGroup := value.(map[string]any); isGroup && anyField(group, predicate) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkMessageOrder(t *testing.T, output string, messages []string) {
+	t.Helper()
+
+	if !containsMessagesInOrder(output, messages) {
+		t.Errorf("output is missing messages %q (or has them out of order):\n%s", messages, output)
+	}
+}
+
+func containsMessagesInOrder(output string, messages []string) bool {
+	remaining := output
+	for _, message := range messages {
+		index := strings.Index(remaining, message)
+		if index == -1 {
+			return false
+		}
+		remaining = remaining[index+len(message):]
+	}
+	return true
+}
+
+func containsAttr(attrs []slog.Attr, expected slog.Attr) bool {
+	for _, attr := range attrs {
+		if attr.Key == expected.Key &&
+			fmt.Sprint(attr.Value.Resolve()) == fmt.Sprint(expected.Value.Resolve()) {
+			return true
+		}
+	}
+	return false
+}
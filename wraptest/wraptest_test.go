@@ -0,0 +1,84 @@
+package wraptest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"hermannm.dev/wrap"
+	"hermannm.dev/wrap/ctxwrap"
+	"hermannm.dev/wrap/wrapslog"
+	"hermannm.dev/wrap/wraptest"
+)
+
+func TestCasesMatchWrap(t *testing.T) {
+	for _, testCase := range wraptest.Cases() {
+		t.Run(testCase.Name, func(t *testing.T) {
+			var messages []string
+			for _, layer := range wrap.Flatten(testCase.Err) {
+				messages = append(messages, layer.Message)
+			}
+			if !slices.Equal(messages, testCase.Messages) {
+				t.Errorf("unexpected messages\nwant: %q\n got: %q", testCase.Messages, messages)
+			}
+
+			attrs := wrap.Attrs(testCase.Err)
+			if !slices.EqualFunc(attrs, testCase.Attrs, slog.Attr.Equal) {
+				t.Errorf("unexpected attrs\nwant: %v\n got: %v", testCase.Attrs, attrs)
+			}
+		})
+	}
+}
+
+func TestFormatterWithSlog(t *testing.T) {
+	wraptest.TestFormatter(t, func(err error) string {
+		var output bytes.Buffer
+		record := slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+		wrapslog.AddError(&record, err)
+		handler := slog.NewJSONHandler(&output, nil)
+		if handleErr := handler.Handle(context.Background(), record); handleErr != nil {
+			t.Fatal(handleErr)
+		}
+		return output.String()
+	})
+}
+
+func TestLoggerWithSlog(t *testing.T) {
+	wraptest.TestLogger(t, func(err error) map[string]any {
+		var output bytes.Buffer
+		record := slog.NewRecord(time.Now(), slog.LevelError, "request failed", 0)
+		wrapslog.AddError(&record, err)
+		handler := slog.NewJSONHandler(&output, nil)
+		if handleErr := handler.Handle(context.Background(), record); handleErr != nil {
+			t.Fatal(handleErr)
+		}
+
+		var fields map[string]any
+		if decodeErr := json.Unmarshal(output.Bytes(), &fields); decodeErr != nil {
+			t.Fatal(decodeErr)
+		}
+		return fields
+	})
+}
+
+func TestWrapperWithWrappingFunctions(t *testing.T) {
+	t.Run("Error", func(t *testing.T) {
+		wraptest.TestWrapper(t, func(err error) error {
+			return wrap.Error(err, "request failed")
+		})
+	})
+	t.Run("Builder", func(t *testing.T) {
+		wraptest.TestWrapper(t, func(err error) error {
+			return wrap.New(err).Attrs("request_id", "abc").Stack().Err()
+		})
+	})
+	t.Run("ctxwrap", func(t *testing.T) {
+		wraptest.TestWrapper(t, func(err error) error {
+			return ctxwrap.Error(context.Background(), err, "request failed")
+		})
+	})
+}